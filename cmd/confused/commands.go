@@ -2,6 +2,7 @@ package main
 
 import (
 	"bufio"
+	"context"
 	"encoding/json"
 	"fmt"
 	"os"
@@ -10,11 +11,22 @@ import (
 	"sync"
 	"time"
 
+	"github.com/h0tak88r/confused/internal/cache"
+	"github.com/h0tak88r/confused/internal/remediation"
 	"github.com/h0tak88r/confused/internal/resolvers"
+	"github.com/h0tak88r/confused/internal/scanners/bitbucket"
+	"github.com/h0tak88r/confused/internal/scanners/gitea"
+	"github.com/h0tak88r/confused/internal/scanners/gitlab"
 	"github.com/h0tak88r/confused/internal/types"
 	"github.com/h0tak88r/confused/pkg/github"
+	"github.com/h0tak88r/confused/pkg/notify"
+	"github.com/h0tak88r/confused/pkg/progress"
+	"github.com/h0tak88r/confused/pkg/registries"
+	"github.com/h0tak88r/confused/pkg/report"
+	"github.com/h0tak88r/confused/pkg/store"
 	"github.com/h0tak88r/confused/pkg/web"
 	"github.com/spf13/cobra"
+	"golang.org/x/time/rate"
 )
 
 // createScanCommand creates the scan command
@@ -23,7 +35,7 @@ func createScanCommand() *cobra.Command {
 		Use:   "scan [flags] <file>",
 		Short: "Scan a local dependency file for dependency confusion vulnerabilities",
 		Long: `Scan a local dependency file for dependency confusion vulnerabilities.
-Supports multiple package managers: npm, pip, composer, mvn, rubygems.
+Supports multiple package managers: npm, pip, composer, mvn, rubygems, go, cargo, nuget.
 
 Examples:
   confused scan package.json
@@ -34,12 +46,34 @@ Examples:
 		RunE: runScanCommand,
 	}
 
-	scanCmd.Flags().StringP("language", "l", "npm", "Package manager (npm, pip, composer, mvn, rubygems)")
+	scanCmd.Flags().StringP("language", "l", "npm", "Package manager (npm, pip, composer, mvn, rubygems, go, cargo, nuget)")
 	scanCmd.Flags().StringSlice("safe-spaces", []string{}, "Known-safe namespaces (supports wildcards)")
 
 	return scanCmd
 }
 
+// createMonorepoCommand creates the monorepo command
+func createMonorepoCommand() *cobra.Command {
+	monorepoCmd := &cobra.Command{
+		Use:   "monorepo [flags] <directory>",
+		Short: "Auto-detect every ecosystem in a directory and scan them together",
+		Long: `Walk a directory for dependency manifests, auto-detecting which package
+managers it uses (npm, pip, composer, mvn, rubygems, go, cargo, nuget), and
+resolve every ecosystem found in a single combined run instead of invoking
+confused once per ecosystem.
+
+Examples:
+  confused monorepo .
+  confused monorepo ./services --safe-spaces "@mycompany/*"`,
+		Args: cobra.ExactArgs(1),
+		RunE: runMonorepoCommand,
+	}
+
+	monorepoCmd.Flags().StringSlice("safe-spaces", []string{}, "Known-safe namespaces (supports wildcards)")
+
+	return monorepoCmd
+}
+
 // createGitHubCommand creates the GitHub scanning command
 func createGitHubCommand() *cobra.Command {
 	githubCmd := &cobra.Command{
@@ -62,10 +96,15 @@ Examples:
 		RunE: runGitHubRepoCommand,
 	}
 
-	repoCmd.Flags().StringSlice("languages", []string{"npm", "pip", "composer", "mvn", "rubygems"}, "Package managers to scan for")
+	repoCmd.Flags().StringSlice("languages", []string{"npm", "pip", "composer", "mvn", "rubygems", "go", "cargo", "nuget"}, "Package managers to scan for")
 	repoCmd.Flags().StringSlice("safe-spaces", []string{}, "Known-safe namespaces (supports wildcards)")
 	repoCmd.Flags().Bool("deep", false, "Perform deep scan including all branches")
 	repoCmd.Flags().IntP("workers", "w", 10, "Number of concurrent workers")
+	repoCmd.Flags().StringSlice("remediate", []string{}, "Turn findings into action: issue (file a GitHub issue), reserve (stage a reservation branch), pr (reserve and open the PR)")
+	repoCmd.Flags().String("remediation-repo", "", "owner/repo to reserve confusable package names in, required for --remediate=reserve/pr (falls back to the remediation_repo config value)")
+	repoCmd.Flags().String("cache-dir", "", "Directory for the on-disk incremental scan cache (falls back to the cache_dir config value)")
+	repoCmd.Flags().Int("cache-ttl", 0, "Registry availability cache TTL in seconds (falls back to the cache_ttl config value)")
+	repoCmd.Flags().Bool("no-cache", false, "Disable the incremental scan cache")
 
 	// GitHub org scan
 	orgCmd := &cobra.Command{
@@ -81,11 +120,14 @@ Examples:
 		RunE: runGitHubOrgCommand,
 	}
 
-	orgCmd.Flags().StringSlice("languages", []string{"npm", "pip", "composer", "mvn", "rubygems"}, "Package managers to scan for")
+	orgCmd.Flags().StringSlice("languages", []string{"npm", "pip", "composer", "mvn", "rubygems", "go", "cargo", "nuget"}, "Package managers to scan for")
 	orgCmd.Flags().StringSlice("safe-spaces", []string{}, "Known-safe namespaces (supports wildcards)")
 	orgCmd.Flags().Int("max-repos", 50, "Maximum number of repositories to scan")
 	orgCmd.Flags().Bool("deep", false, "Perform deep scan including all branches")
 	orgCmd.Flags().IntP("workers", "w", 10, "Number of concurrent workers")
+	orgCmd.Flags().String("cache-dir", "", "Directory for the on-disk incremental scan cache (falls back to the cache_dir config value)")
+	orgCmd.Flags().Int("cache-ttl", 0, "Registry availability cache TTL in seconds (falls back to the cache_ttl config value)")
+	orgCmd.Flags().Bool("no-cache", false, "Disable the incremental scan cache")
 
 	githubCmd.AddCommand(repoCmd)
 	githubCmd.AddCommand(orgCmd)
@@ -93,6 +135,147 @@ Examples:
 	return githubCmd
 }
 
+// createGitLabCommand creates the GitLab scanning command
+func createGitLabCommand() *cobra.Command {
+	gitlabCmd := &cobra.Command{
+		Use:   "gitlab",
+		Short: "GitLab scanning commands",
+		Long:  `Scan GitLab projects and groups for dependency confusion vulnerabilities.`,
+	}
+
+	repoCmd := &cobra.Command{
+		Use:   "repo <group/project>",
+		Short: "Scan a specific GitLab project",
+		Long: `Scan a specific GitLab project for dependency files and check for dependency confusion vulnerabilities.
+
+Examples:
+  confused gitlab repo gitlab-org/gitlab
+  confused gitlab repo mygroup/myproject --gitlab-url https://gitlab.example.com`,
+		Args: cobra.ExactArgs(1),
+		RunE: runGitLabRepoCommand,
+	}
+	repoCmd.Flags().StringSlice("languages", []string{"npm", "pip", "composer", "mvn", "rubygems", "go", "cargo", "nuget"}, "Package managers to scan for")
+	repoCmd.Flags().StringSlice("safe-spaces", []string{}, "Known-safe namespaces (supports wildcards)")
+	repoCmd.Flags().Bool("deep", false, "Perform deep scan including all branches")
+	repoCmd.Flags().String("gitlab-token", "", "GitLab API token (falls back to the providers.gitlab.token config value)")
+	repoCmd.Flags().String("gitlab-url", "", "GitLab instance base URL (defaults to https://gitlab.com)")
+
+	orgCmd := &cobra.Command{
+		Use:   "group <group>",
+		Short: "Scan a GitLab group",
+		Long: `Scan all projects in a GitLab group for dependency confusion vulnerabilities.
+
+Examples:
+  confused gitlab group gitlab-org
+  confused gitlab group mygroup --max-repos 100`,
+		Args: cobra.ExactArgs(1),
+		RunE: runGitLabOrgCommand,
+	}
+	orgCmd.Flags().StringSlice("languages", []string{"npm", "pip", "composer", "mvn", "rubygems", "go", "cargo", "nuget"}, "Package managers to scan for")
+	orgCmd.Flags().StringSlice("safe-spaces", []string{}, "Known-safe namespaces (supports wildcards)")
+	orgCmd.Flags().Int("max-repos", 50, "Maximum number of projects to scan")
+	orgCmd.Flags().Bool("deep", false, "Perform deep scan including all branches")
+	orgCmd.Flags().String("gitlab-token", "", "GitLab API token (falls back to the providers.gitlab.token config value)")
+	orgCmd.Flags().String("gitlab-url", "", "GitLab instance base URL (defaults to https://gitlab.com)")
+
+	gitlabCmd.AddCommand(repoCmd)
+	gitlabCmd.AddCommand(orgCmd)
+
+	return gitlabCmd
+}
+
+// createBitbucketCommand creates the Bitbucket scanning command
+func createBitbucketCommand() *cobra.Command {
+	bitbucketCmd := &cobra.Command{
+		Use:   "bitbucket",
+		Short: "Bitbucket scanning commands",
+		Long:  `Scan Bitbucket Cloud repositories and workspaces for dependency confusion vulnerabilities.`,
+	}
+
+	repoCmd := &cobra.Command{
+		Use:   "repo <workspace/repo_slug>",
+		Short: "Scan a specific Bitbucket repository",
+		Long: `Scan a specific Bitbucket Cloud repository for dependency files and check for dependency confusion vulnerabilities.
+
+Examples:
+  confused bitbucket repo myworkspace/myrepo`,
+		Args: cobra.ExactArgs(1),
+		RunE: runBitbucketRepoCommand,
+	}
+	repoCmd.Flags().StringSlice("languages", []string{"npm", "pip", "composer", "mvn", "rubygems", "go", "cargo", "nuget"}, "Package managers to scan for")
+	repoCmd.Flags().StringSlice("safe-spaces", []string{}, "Known-safe namespaces (supports wildcards)")
+	repoCmd.Flags().Bool("deep", false, "Perform deep scan including all branches")
+	repoCmd.Flags().String("bitbucket-token", "", "Bitbucket access token (falls back to the providers.bitbucket.token config value)")
+
+	orgCmd := &cobra.Command{
+		Use:   "workspace <workspace>",
+		Short: "Scan a Bitbucket workspace",
+		Long: `Scan all repositories in a Bitbucket workspace for dependency confusion vulnerabilities.
+
+Examples:
+  confused bitbucket workspace myworkspace --max-repos 100`,
+		Args: cobra.ExactArgs(1),
+		RunE: runBitbucketOrgCommand,
+	}
+	orgCmd.Flags().StringSlice("languages", []string{"npm", "pip", "composer", "mvn", "rubygems", "go", "cargo", "nuget"}, "Package managers to scan for")
+	orgCmd.Flags().StringSlice("safe-spaces", []string{}, "Known-safe namespaces (supports wildcards)")
+	orgCmd.Flags().Int("max-repos", 50, "Maximum number of repositories to scan")
+	orgCmd.Flags().Bool("deep", false, "Perform deep scan including all branches")
+	orgCmd.Flags().String("bitbucket-token", "", "Bitbucket access token (falls back to the providers.bitbucket.token config value)")
+
+	bitbucketCmd.AddCommand(repoCmd)
+	bitbucketCmd.AddCommand(orgCmd)
+
+	return bitbucketCmd
+}
+
+// createGiteaCommand creates the Gitea scanning command
+func createGiteaCommand() *cobra.Command {
+	giteaCmd := &cobra.Command{
+		Use:   "gitea",
+		Short: "Gitea scanning commands",
+		Long:  `Scan Gitea repositories and organizations for dependency confusion vulnerabilities.`,
+	}
+
+	repoCmd := &cobra.Command{
+		Use:   "repo <owner/repo>",
+		Short: "Scan a specific Gitea repository",
+		Long: `Scan a specific Gitea repository for dependency files and check for dependency confusion vulnerabilities.
+
+Examples:
+  confused gitea repo myorg/myrepo --gitea-url https://gitea.example.com`,
+		Args: cobra.ExactArgs(1),
+		RunE: runGiteaRepoCommand,
+	}
+	repoCmd.Flags().StringSlice("languages", []string{"npm", "pip", "composer", "mvn", "rubygems", "go", "cargo", "nuget"}, "Package managers to scan for")
+	repoCmd.Flags().StringSlice("safe-spaces", []string{}, "Known-safe namespaces (supports wildcards)")
+	repoCmd.Flags().Bool("deep", false, "Perform deep scan including all branches")
+	repoCmd.Flags().String("gitea-token", "", "Gitea API token (falls back to the providers.gitea.token config value)")
+	repoCmd.Flags().String("gitea-url", "", "Gitea instance base URL (defaults to https://gitea.com)")
+
+	orgCmd := &cobra.Command{
+		Use:   "org <organization>",
+		Short: "Scan a Gitea organization",
+		Long: `Scan all repositories in a Gitea organization for dependency confusion vulnerabilities.
+
+Examples:
+  confused gitea org myorg --max-repos 100`,
+		Args: cobra.ExactArgs(1),
+		RunE: runGiteaOrgCommand,
+	}
+	orgCmd.Flags().StringSlice("languages", []string{"npm", "pip", "composer", "mvn", "rubygems", "go", "cargo", "nuget"}, "Package managers to scan for")
+	orgCmd.Flags().StringSlice("safe-spaces", []string{}, "Known-safe namespaces (supports wildcards)")
+	orgCmd.Flags().Int("max-repos", 50, "Maximum number of repositories to scan")
+	orgCmd.Flags().Bool("deep", false, "Perform deep scan including all branches")
+	orgCmd.Flags().String("gitea-token", "", "Gitea API token (falls back to the providers.gitea.token config value)")
+	orgCmd.Flags().String("gitea-url", "", "Gitea instance base URL (defaults to https://gitea.com)")
+
+	giteaCmd.AddCommand(repoCmd)
+	giteaCmd.AddCommand(orgCmd)
+
+	return giteaCmd
+}
+
 // createWebCommand creates the web scanning command
 func createWebCommand() *cobra.Command {
 	webCmd := &cobra.Command{
@@ -102,13 +285,14 @@ func createWebCommand() *cobra.Command {
 This command will attempt to discover dependency files through various methods including:
 - Common file paths (package.json, requirements.txt, etc.)
 - Directory brute forcing
-- Sitemap analysis
+- Sitemap and robots.txt analysis (enable with --discovery robots,sitemap)
 - Response analysis
 
 Examples:
   confused web https://example.com
   confused web https://example.com https://app.example.com
   confused web example.com --deep --workers 20
+  confused web example.com --deep --discovery robots,sitemap
   confused web --target-file targets.txt`,
 		Args: func(cmd *cobra.Command, args []string) error {
 			targetFile, _ := cmd.Flags().GetString("target-file")
@@ -125,13 +309,14 @@ Examples:
 		RunE: runWebCommand,
 	}
 
-	webCmd.Flags().StringSlice("languages", []string{"npm", "pip", "composer", "mvn", "rubygems"}, "Package managers to scan for")
+	webCmd.Flags().StringSlice("languages", []string{"npm", "pip", "composer", "mvn", "rubygems", "go", "cargo", "nuget"}, "Package managers to scan for")
 	webCmd.Flags().StringSlice("safe-spaces", []string{}, "Known-safe namespaces (supports wildcards)")
 	webCmd.Flags().Bool("deep", false, "Perform deep scan with extensive file discovery")
 	webCmd.Flags().StringSlice("wordlist", []string{}, "Custom wordlist for file discovery")
 	webCmd.Flags().Int("max-depth", 3, "Maximum directory depth for discovery")
 	webCmd.Flags().String("target-file", "", "File containing list of targets (one per line)")
 	webCmd.Flags().IntP("workers", "w", 10, "Number of concurrent workers")
+	webCmd.Flags().StringSlice("discovery", []string{}, "Discovery sources to consult for extra app roots when --deep is set (robots,sitemap,commoncrawl)")
 
 	return webCmd
 }
@@ -168,22 +353,90 @@ func createConfigCommand() *cobra.Command {
 	return configCmd
 }
 
+// createHistoryCommand creates the history command
+func createHistoryCommand() *cobra.Command {
+	historyCmd := &cobra.Command{
+		Use:   "history <target>",
+		Short: "List previous scans of a target and diff the two most recent runs",
+		Long: `Show previously stored scans of a target (requires the "database" section
+of the config to be set) and print the packages that newly appeared as
+vulnerable between the last two runs, so CI can fail builds only on regression.
+
+Examples:
+  confused history package.json`,
+		Args: cobra.ExactArgs(1),
+		RunE: runHistoryCommand,
+	}
+
+	return historyCmd
+}
+
+// runHistoryCommand runs the history command
+func runHistoryCommand(cmd *cobra.Command, args []string) error {
+	target := args[0]
+
+	resultStore, err := store.New(cfg)
+	if err != nil {
+		return fmt.Errorf("failed to initialize result store: %w", err)
+	}
+	if resultStore == nil {
+		return fmt.Errorf("no database configured; set database.type in confused.yaml to \"sqlite\" or \"postgresql\"")
+	}
+	defer resultStore.Close()
+
+	results, err := resultStore.ListResults(target)
+	if err != nil {
+		return fmt.Errorf("failed to list scan history: %w", err)
+	}
+
+	if len(results) == 0 {
+		log.Info("No stored scans found for %s", target)
+		return nil
+	}
+
+	log.Info("Found %d stored scan(s) for %s:", len(results), target)
+	for _, result := range results {
+		log.Info(" - %s: %d vulnerable / %d total", result.Timestamp.Format(time.RFC3339), len(result.Vulnerable), result.Total)
+	}
+
+	diff, err := resultStore.DiffAgainstPrevious(target)
+	if err != nil {
+		log.Info("%v", err)
+		return nil
+	}
+
+	if len(diff.NewlyVulnerable) == 0 {
+		log.Info("No newly vulnerable packages since the previous scan (%s -> %s)", diff.Previous, diff.Current)
+		return nil
+	}
+
+	log.Warn("Newly vulnerable packages since %s:", diff.Previous)
+	for _, pkg := range diff.NewlyVulnerable {
+		log.Warn(" [!] %s", pkg)
+	}
+
+	return nil
+}
+
 // runScanCommand runs the scan command
 func runScanCommand(cmd *cobra.Command, args []string) error {
 	filename := args[0]
 	language, _ := cmd.Flags().GetString("language")
 	safeSpaces, _ := cmd.Flags().GetStringSlice("safe-spaces")
 
-	log.Info("Starting dependency confusion scan...")
-	log.Info("Target: %s", filename)
-	log.Info("Language: %s", language)
-	log.Info("Workers: %d", cfg.Workers)
+	scanLog := log.WithFields(map[string]interface{}{"target": filename, "language": language})
+	scanLog.Info("Starting dependency confusion scan...")
+	scanLog.Info("Workers: %d", cfg.Workers)
 
 	// Create scan result
 	result := types.NewScanResult(filename, "file", language)
 
 	// Get resolver for the language
-	resolver, err := resolvers.GetResolverForLanguageWithVerbose(language, cfg.Verbose)
+	regs, err := newRegistrySet()
+	if err != nil {
+		return fmt.Errorf("failed to parse registry flags: %w", err)
+	}
+	resolver, err := resolvers.GetResolverForLanguageWithContext(language, cfg.Verbose, cmd.Context(), newRateLimiter(), regs, log, cfg.Workers)
 	if err != nil {
 		return fmt.Errorf("failed to get resolver for language %s: %w", language, err)
 	}
@@ -193,9 +446,12 @@ func runScanCommand(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to read packages from file: %w", err)
 	}
 
-	// Get vulnerable packages
+	// Get vulnerable packages, aborting early if the scan was cancelled
+	if err := resolver.ResolveWithContext(cmd.Context()); err != nil {
+		return fmt.Errorf("scan cancelled: %w", err)
+	}
 	vulnerablePackages := resolver.PackagesNotInPublic()
-	
+
 	// Remove safe spaces
 	vulnerablePackages = removeSafe(vulnerablePackages, safeSpaces)
 
@@ -207,8 +463,17 @@ func runScanCommand(cmd *cobra.Command, args []string) error {
 	// Finalize result
 	result.Finalize()
 
+	log.WithFields(map[string]interface{}{
+		"target":              filename,
+		"language":            language,
+		"duration_ms":         result.Duration.Milliseconds(),
+		"packages_total":      result.Total,
+		"packages_vulnerable": len(result.Vulnerable),
+	}).Info("Scan complete")
+
 	// Print result
 	printResult(result)
+	notifyIfVulnerable(result)
 
 	// Save results if requested
 	if cfg.SaveResults {
@@ -225,6 +490,92 @@ func runScanCommand(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// runMonorepoCommand runs the monorepo command
+func runMonorepoCommand(cmd *cobra.Command, args []string) error {
+	root := args[0]
+	safeSpaces, _ := cmd.Flags().GetStringSlice("safe-spaces")
+
+	detected, err := resolvers.DetectLanguages(root)
+	if err != nil {
+		return fmt.Errorf("failed to detect ecosystems in %s: %w", root, err)
+	}
+	if len(detected) == 0 {
+		log.Info("No known dependency manifests found under %s", root)
+		return nil
+	}
+
+	regs, err := newRegistrySet()
+	if err != nil {
+		return fmt.Errorf("failed to parse registry flags: %w", err)
+	}
+	cacheStore, cacheTTL, err := newCacheStore(cmd)
+	if err != nil {
+		return fmt.Errorf("failed to initialize cache: %w", err)
+	}
+
+	var resolverList []types.PackageResolver
+	pkgs := make(map[string][]string, len(detected))
+	for _, lang := range detected {
+		resolver, err := resolvers.GetResolverForLanguageWithCache(lang.Language, cfg.Verbose, cmd.Context(), newRateLimiter(), regs, log, cfg.Workers, cacheStore, cacheTTL)
+		if err != nil {
+			log.Warn("Skipping %s: %v", lang.Language, err)
+			continue
+		}
+		resolverList = append(resolverList, resolver)
+		pkgs[lang.Language] = lang.ManifestPaths
+	}
+
+	log.Info("Detected %d ecosystem(s) under %s: %v", len(resolverList), root, pkgs)
+
+	results, err := resolvers.ResolveAll(cmd.Context(), resolverList, pkgs)
+	if err != nil {
+		return fmt.Errorf("monorepo scan cancelled: %w", err)
+	}
+
+	var scanResults []types.ScanResult
+	vulnerable := false
+	for _, lang := range detected {
+		langResult, ok := results.Languages[lang.Language]
+		if !ok {
+			continue
+		}
+		if langResult.Err != nil {
+			log.Warn("Scan of %s failed: %v", lang.Language, langResult.Err)
+			continue
+		}
+
+		result := types.NewScanResult(root, "directory", lang.Language)
+		for _, pkg := range removeSafe(langResult.Confused, safeSpaces) {
+			result.AddVulnerable(pkg)
+		}
+		result.Finalize()
+
+		printResult(result)
+		notifyIfVulnerable(result)
+		scanResults = append(scanResults, *result)
+		vulnerable = vulnerable || result.IsVulnerable()
+	}
+
+	log.WithFields(map[string]interface{}{
+		"target":              root,
+		"ecosystems":          len(scanResults),
+		"registry_requests":   results.Metrics.Requests,
+		"registry_cache_hits": results.Metrics.CacheHits,
+	}).Info("Monorepo scan complete")
+
+	if cfg.SaveResults {
+		if err := saveScanResults(scanResults); err != nil {
+			log.Warn("Failed to save results: %v", err)
+		}
+	}
+
+	if vulnerable {
+		os.Exit(1)
+	}
+
+	return nil
+}
+
 // runGitHubRepoCommand runs the GitHub repository scan command
 func runGitHubRepoCommand(cmd *cobra.Command, args []string) error {
 	repo := args[0]
@@ -232,12 +583,17 @@ func runGitHubRepoCommand(cmd *cobra.Command, args []string) error {
 	safeSpaces, _ := cmd.Flags().GetStringSlice("safe-spaces")
 	deep, _ := cmd.Flags().GetBool("deep")
 	workers, _ := cmd.Flags().GetInt("workers")
+	remediate, _ := cmd.Flags().GetStringSlice("remediate")
+	remediationRepo, _ := cmd.Flags().GetString("remediation-repo")
+	if remediationRepo == "" {
+		remediationRepo = cfg.RemediationRepo
+	}
 
-	log.Info("Starting GitHub repository scan...")
-	log.Info("Repository: %s", repo)
-	log.Info("Languages: %s", strings.Join(languages, ", "))
-	log.Info("Deep scan: %v", deep)
-	log.Info("Workers: %d", workers)
+	repoLog := log.WithFields(map[string]interface{}{"repo": repo})
+	repoLog.Info("Starting GitHub repository scan...")
+	repoLog.Info("Languages: %s", strings.Join(languages, ", "))
+	repoLog.Info("Deep scan: %v", deep)
+	repoLog.Info("Workers: %d", workers)
 
 	// Check if GitHub token is provided
 	if cfg.GitHubToken == "" {
@@ -250,6 +606,19 @@ func runGitHubRepoCommand(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		return fmt.Errorf("failed to initialize GitHub client: %w", err)
 	}
+	githubClient.SetContext(cmd.Context())
+	githubClient.SetLimiter(newRateLimiter())
+	regs, err := newRegistrySet()
+	if err != nil {
+		return fmt.Errorf("failed to parse registry flags: %w", err)
+	}
+	githubClient.SetRegistries(regs)
+	cacheStore, cacheTTL, err := newCacheStore(cmd)
+	if err != nil {
+		return fmt.Errorf("failed to initialize scan cache: %w", err)
+	}
+	defer cacheStore.Close()
+	githubClient.SetCache(cacheStore, cacheTTL)
 
 	// Scan repository
 	results, err := githubClient.ScanRepository(repo, languages, safeSpaces, deep)
@@ -257,9 +626,28 @@ func runGitHubRepoCommand(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to scan repository: %w", err)
 	}
 
+	vulnerableCount := 0
+	for _, result := range results {
+		vulnerableCount += len(result.Vulnerable)
+	}
+	repoLog.WithFields(map[string]interface{}{
+		"packages_total":      len(results),
+		"packages_vulnerable": vulnerableCount,
+	}).Info("GitHub repository scan complete")
+
 	// Process results
 	for _, result := range results {
 		printResult(result)
+		notifyIfVulnerable(result)
+	}
+
+	// Turn findings into action, if requested
+	if len(remediate) > 0 {
+		parts := strings.SplitN(repo, "/", 2)
+		rem := remediation.New(githubClient.RawClient(), log)
+		if err := rem.Run(cmd.Context(), parts[0], parts[1], remediate, remediationRepo, results); err != nil {
+			log.Warn("Remediation failed: %v", err)
+		}
 	}
 
 	// Save results if requested
@@ -304,6 +692,20 @@ func runGitHubOrgCommand(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		return fmt.Errorf("failed to initialize GitHub client: %w", err)
 	}
+	githubClient.SetProgress(progress.New(cfg.NoProgress, cfg.LogFormat))
+	githubClient.SetContext(cmd.Context())
+	githubClient.SetLimiter(newRateLimiter())
+	regs, err := newRegistrySet()
+	if err != nil {
+		return fmt.Errorf("failed to parse registry flags: %w", err)
+	}
+	githubClient.SetRegistries(regs)
+	cacheStore, cacheTTL, err := newCacheStore(cmd)
+	if err != nil {
+		return fmt.Errorf("failed to initialize scan cache: %w", err)
+	}
+	defer cacheStore.Close()
+	githubClient.SetCache(cacheStore, cacheTTL)
 
 	// Scan organization
 	results, err := githubClient.ScanOrganization(org, languages, safeSpaces, maxRepos, deep)
@@ -314,6 +716,7 @@ func runGitHubOrgCommand(cmd *cobra.Command, args []string) error {
 	// Process results
 	for _, result := range results {
 		printResult(result)
+		notifyIfVulnerable(result)
 	}
 
 	// Save results if requested
@@ -331,6 +734,183 @@ func runGitHubOrgCommand(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// providerToken returns the token to use for a non-GitHub provider: the
+// command's own flag value if set, otherwise the providers.<name>.token
+// config value.
+func providerToken(flagValue string, providerName string) string {
+	if flagValue != "" {
+		return flagValue
+	}
+	return cfg.Providers[providerName].Token
+}
+
+// providerBaseURL returns the base URL to use for a self-hostable provider:
+// the command's own flag value if set, otherwise the providers.<name>.base_url
+// config value.
+func providerBaseURL(flagValue string, providerName string) string {
+	if flagValue != "" {
+		return flagValue
+	}
+	return cfg.Providers[providerName].BaseURL
+}
+
+// runVCSScan scans a single repository or an organization/group/workspace
+// through scanner (any types.VCSScanner implementation), then prints,
+// notifies, and saves the results the same way every other scan command
+// does - shared here so the gitlab/bitbucket/gitea commands below don't
+// each repeat the GitHub command's result-handling boilerplate.
+func runVCSScan(scanner types.VCSScanner, ctx context.Context, target string, languages []string, safeSpaces []string, maxRepos int, deep bool, isOrg bool) error {
+	scanner.SetContext(ctx)
+	scanner.SetLimiter(newRateLimiter())
+	regs, err := newRegistrySet()
+	if err != nil {
+		return fmt.Errorf("failed to parse registry flags: %w", err)
+	}
+	scanner.SetRegistries(regs)
+
+	var results []*types.ScanResult
+	if isOrg {
+		results, err = scanner.ScanOrganization(target, languages, safeSpaces, maxRepos, deep)
+		if err != nil {
+			return fmt.Errorf("failed to scan organization: %w", err)
+		}
+	} else {
+		results, err = scanner.ScanRepository(target, languages, safeSpaces, deep)
+		if err != nil {
+			return fmt.Errorf("failed to scan repository: %w", err)
+		}
+	}
+
+	for _, result := range results {
+		printResult(result)
+		notifyIfVulnerable(result)
+	}
+
+	if cfg.SaveResults {
+		convertedResults := make([]types.ScanResult, len(results))
+		for i, result := range results {
+			convertedResults[i] = *result
+		}
+		if err := saveScanResults(convertedResults); err != nil {
+			log.Warn("Failed to save results: %v", err)
+		}
+	}
+
+	return nil
+}
+
+// runGitLabRepoCommand runs the GitLab project scan command
+func runGitLabRepoCommand(cmd *cobra.Command, args []string) error {
+	project := args[0]
+	languages, _ := cmd.Flags().GetStringSlice("languages")
+	safeSpaces, _ := cmd.Flags().GetStringSlice("safe-spaces")
+	deep, _ := cmd.Flags().GetBool("deep")
+	token, _ := cmd.Flags().GetString("gitlab-token")
+	baseURL, _ := cmd.Flags().GetString("gitlab-url")
+
+	log.Info("Starting GitLab project scan...")
+	log.Info("Project: %s", project)
+	log.Info("Languages: %s", strings.Join(languages, ", "))
+	log.Info("Deep scan: %v", deep)
+
+	client := gitlab.New(cfg, log, providerBaseURL(baseURL, "gitlab"), providerToken(token, "gitlab"))
+	return runVCSScan(client, cmd.Context(), project, languages, safeSpaces, 0, deep, false)
+}
+
+// runGitLabOrgCommand runs the GitLab group scan command
+func runGitLabOrgCommand(cmd *cobra.Command, args []string) error {
+	group := args[0]
+	languages, _ := cmd.Flags().GetStringSlice("languages")
+	safeSpaces, _ := cmd.Flags().GetStringSlice("safe-spaces")
+	maxRepos, _ := cmd.Flags().GetInt("max-repos")
+	deep, _ := cmd.Flags().GetBool("deep")
+	token, _ := cmd.Flags().GetString("gitlab-token")
+	baseURL, _ := cmd.Flags().GetString("gitlab-url")
+
+	log.Info("Starting GitLab group scan...")
+	log.Info("Group: %s", group)
+	log.Info("Languages: %s", strings.Join(languages, ", "))
+	log.Info("Max projects: %d", maxRepos)
+	log.Info("Deep scan: %v", deep)
+
+	client := gitlab.New(cfg, log, providerBaseURL(baseURL, "gitlab"), providerToken(token, "gitlab"))
+	return runVCSScan(client, cmd.Context(), group, languages, safeSpaces, maxRepos, deep, true)
+}
+
+// runBitbucketRepoCommand runs the Bitbucket repository scan command
+func runBitbucketRepoCommand(cmd *cobra.Command, args []string) error {
+	repo := args[0]
+	languages, _ := cmd.Flags().GetStringSlice("languages")
+	safeSpaces, _ := cmd.Flags().GetStringSlice("safe-spaces")
+	deep, _ := cmd.Flags().GetBool("deep")
+	token, _ := cmd.Flags().GetString("bitbucket-token")
+
+	log.Info("Starting Bitbucket repository scan...")
+	log.Info("Repository: %s", repo)
+	log.Info("Languages: %s", strings.Join(languages, ", "))
+	log.Info("Deep scan: %v", deep)
+
+	client := bitbucket.New(cfg, log, providerToken(token, "bitbucket"))
+	return runVCSScan(client, cmd.Context(), repo, languages, safeSpaces, 0, deep, false)
+}
+
+// runBitbucketOrgCommand runs the Bitbucket workspace scan command
+func runBitbucketOrgCommand(cmd *cobra.Command, args []string) error {
+	workspace := args[0]
+	languages, _ := cmd.Flags().GetStringSlice("languages")
+	safeSpaces, _ := cmd.Flags().GetStringSlice("safe-spaces")
+	maxRepos, _ := cmd.Flags().GetInt("max-repos")
+	deep, _ := cmd.Flags().GetBool("deep")
+	token, _ := cmd.Flags().GetString("bitbucket-token")
+
+	log.Info("Starting Bitbucket workspace scan...")
+	log.Info("Workspace: %s", workspace)
+	log.Info("Languages: %s", strings.Join(languages, ", "))
+	log.Info("Max repositories: %d", maxRepos)
+	log.Info("Deep scan: %v", deep)
+
+	client := bitbucket.New(cfg, log, providerToken(token, "bitbucket"))
+	return runVCSScan(client, cmd.Context(), workspace, languages, safeSpaces, maxRepos, deep, true)
+}
+
+// runGiteaRepoCommand runs the Gitea repository scan command
+func runGiteaRepoCommand(cmd *cobra.Command, args []string) error {
+	repo := args[0]
+	languages, _ := cmd.Flags().GetStringSlice("languages")
+	safeSpaces, _ := cmd.Flags().GetStringSlice("safe-spaces")
+	deep, _ := cmd.Flags().GetBool("deep")
+	token, _ := cmd.Flags().GetString("gitea-token")
+	baseURL, _ := cmd.Flags().GetString("gitea-url")
+
+	log.Info("Starting Gitea repository scan...")
+	log.Info("Repository: %s", repo)
+	log.Info("Languages: %s", strings.Join(languages, ", "))
+	log.Info("Deep scan: %v", deep)
+
+	client := gitea.New(cfg, log, providerBaseURL(baseURL, "gitea"), providerToken(token, "gitea"))
+	return runVCSScan(client, cmd.Context(), repo, languages, safeSpaces, 0, deep, false)
+}
+
+// runGiteaOrgCommand runs the Gitea organization scan command
+func runGiteaOrgCommand(cmd *cobra.Command, args []string) error {
+	org := args[0]
+	languages, _ := cmd.Flags().GetStringSlice("languages")
+	safeSpaces, _ := cmd.Flags().GetStringSlice("safe-spaces")
+	maxRepos, _ := cmd.Flags().GetInt("max-repos")
+	deep, _ := cmd.Flags().GetBool("deep")
+	token, _ := cmd.Flags().GetString("gitea-token")
+	baseURL, _ := cmd.Flags().GetString("gitea-url")
+
+	log.Info("Starting Gitea organization scan...")
+	log.Info("Organization: %s", org)
+	log.Info("Languages: %s", strings.Join(languages, ", "))
+	log.Info("Max repositories: %d", maxRepos)
+	log.Info("Deep scan: %v", deep)
+
+	client := gitea.New(cfg, log, providerBaseURL(baseURL, "gitea"), providerToken(token, "gitea"))
+	return runVCSScan(client, cmd.Context(), org, languages, safeSpaces, maxRepos, deep, true)
+}
+
 // runWebCommand runs the web scanning command
 func runWebCommand(cmd *cobra.Command, args []string) error {
 	languages, _ := cmd.Flags().GetStringSlice("languages")
@@ -338,6 +918,7 @@ func runWebCommand(cmd *cobra.Command, args []string) error {
 	maxDepth, _ := cmd.Flags().GetInt("max-depth")
 	targetFile, _ := cmd.Flags().GetString("target-file")
 	workers, _ := cmd.Flags().GetInt("workers")
+	discovery, _ := cmd.Flags().GetStringSlice("discovery")
 
 	// Get targets from command line args or target file
 	var targets []string
@@ -357,12 +938,25 @@ func runWebCommand(cmd *cobra.Command, args []string) error {
 	log.Info("Deep scan: %v", deep)
 	log.Info("Max depth: %d", maxDepth)
 	log.Info("Workers: %d", workers)
+	if len(discovery) > 0 {
+		log.Info("Discovery sources: %s", strings.Join(discovery, ", "))
+	}
 
 	// Initialize web scanner
 	webScanner := web.New(log, cfg.UserAgent, cfg.GetTimeout())
+	webScanner.SetContext(cmd.Context())
+	webScanner.SetLimiter(newRateLimiter())
+	webScanner.SetWorkers(workers)
+	webScanner.SetDiscovery(discovery)
+	regs, err := newRegistrySet()
+	if err != nil {
+		return fmt.Errorf("failed to parse registry flags: %w", err)
+	}
+	webScanner.SetRegistries(regs)
 
 	// Process targets with worker pool
-	results, err := processWebTargetsWithWorkers(webScanner, targets, languages, deep, maxDepth, workers)
+	webProgress := progress.New(cfg.NoProgress, cfg.LogFormat)
+	results, err := processWebTargetsWithWorkers(webScanner, targets, languages, deep, maxDepth, workers, webProgress)
 	if err != nil {
 		return fmt.Errorf("failed to scan web targets: %w", err)
 	}
@@ -370,6 +964,7 @@ func runWebCommand(cmd *cobra.Command, args []string) error {
 	// Process results
 	for _, result := range results {
 		printResult(result)
+		notifyIfVulnerable(result)
 	}
 
 	// Save results if requested
@@ -399,7 +994,7 @@ func runConfigGenerateCommand(cmd *cobra.Command, args []string) error {
 # General settings
 verbose: false
 output: ""
-format: "text"  # text, json, html
+format: "text"  # text, json, html, sarif, spdx
 workers: 10
 timeout: 30
 
@@ -409,15 +1004,42 @@ github_org: ""
 github_repo: ""
 max_repos: 50
 
+# Remediation - turn a scan into a closed-loop defense (github repo only)
+remediate: []  # any of: issue, reserve, pr
+remediation_repo: ""  # "owner/repo" to reserve confusable names in, for reserve/pr
+
+# Incremental scan cache - skip re-downloading/re-resolving dependency
+# blobs and registry lookups a prior scan already saw
+cache_dir: "./.confused-cache"
+cache_ttl: 3600  # seconds a cached registry availability check stays valid
+no_cache: false
+
 # Target settings
 targets: []
 target_file: ""
 
 # Scanning settings
 safe_spaces: []  # Known-safe namespaces (supports wildcards)
-languages: ["npm", "pip", "composer", "mvn", "rubygems"]
+languages: ["npm", "pip", "composer", "mvn", "rubygems", "go", "cargo", "nuget"]
 deep_scan: false
 
+# Registry mirrors - "ecosystem=url" or "ecosystem=url|Header-Name: value"
+registries: []           # public mirrors, e.g. "mvn=https://nexus.internal/repository/maven-public"
+internal_registries: []  # internal-only registries; a package is only confused if absent from every
+                          # public registry above AND present in one of these (when any are configured)
+
+# Structured alternative to registries/internal_registries above, one entry
+# per ecosystem, for when a mirror needs a proxy, a self-signed cert, or its
+# own timeout rather than just a URL and an auth header:
+# registry_config:
+#   npm:
+#     url: "https://nexus.internal/repository/npm-public"
+#     auth_header: "Authorization: Bearer s3cr3t"
+#     internal: false
+#     timeout: 15                        # seconds, 0 = use the resolver's own timeout
+#     proxy: "http://proxy.internal:8080"
+#     insecure_skip_verify: false
+
 # Rate limiting
 rate_limit: 100
 delay: 100
@@ -438,6 +1060,17 @@ database:
   username: ""
   password: ""
   database: "confused"
+
+# Notifications - route findings to chat/ticketing/audit-log destinations
+notifications:
+  - type: "slack"
+    enabled: false
+    settings:
+      webhook_url: ""
+  - type: "file"
+    enabled: false
+    settings:
+      path: "./results/findings.ndjson"
 `
 
 	// Write config file
@@ -468,7 +1101,7 @@ func runConfigValidateCommand(cmd *cobra.Command, args []string) error {
 	}
 
 	// Validate languages
-	validLanguages := []string{"npm", "pip", "composer", "mvn", "rubygems"}
+	validLanguages := []string{"npm", "pip", "composer", "mvn", "rubygems", "go", "cargo", "nuget"}
 	for _, lang := range cfg.Languages {
 		valid := false
 		for _, validLang := range validLanguages {
@@ -486,22 +1119,73 @@ func runConfigValidateCommand(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// notifyIfVulnerable fans a vulnerable result out to every enabled notifier
+// configured under the "notifications" section of confused.yaml.
+func notifyIfVulnerable(result *types.ScanResult) {
+	if !result.IsVulnerable() || len(cfg.Notifications) == 0 {
+		return
+	}
+
+	notifiers, err := notify.Build(cfg.Notifications, log)
+	if err != nil {
+		log.Warn("Failed to build notifiers: %v", err)
+		return
+	}
+
+	notify.Dispatch(context.Background(), notifiers, result, log)
+}
+
 // printResult outputs the result of the scanner
 func printResult(result *types.ScanResult) {
+	// JSON/SARIF/SPDX are structured formats meant for tooling, not the
+	// console; route them through pkg/report instead of the human-readable
+	// summary.
+	if cfg.Format == "json" || cfg.Format == "sarif" || cfg.Format == "spdx" {
+		reporter, err := report.ForFormat(cfg.Format)
+		if err != nil {
+			log.Warn("Failed to get reporter for format %s: %v", cfg.Format, err)
+			return
+		}
+		if err := reporter.Write(os.Stdout, result); err != nil {
+			log.Warn("Failed to write %s report: %v", cfg.Format, err)
+		}
+		return
+	}
+
 	if !result.IsVulnerable() {
 		log.Info("All packages seem to be available in the public repositories.")
 		log.Info("In case your application uses private repositories please make sure that those namespaces in public repositories are controlled by a trusted party.")
 		return
 	}
-	
+
 	log.Warn("Issues found, the following packages are not available in public package repositories:")
 	for _, pkg := range result.Vulnerable {
 		log.Warn(" [!] %s", pkg)
 	}
 }
 
+// saveToHistory persists result to the configured database, if any. It is a
+// no-op when no database.type is set in the config.
+func saveToHistory(result *types.ScanResult) {
+	resultStore, err := store.New(cfg)
+	if err != nil {
+		log.Warn("Failed to initialize result store: %v", err)
+		return
+	}
+	if resultStore == nil {
+		return
+	}
+	defer resultStore.Close()
+
+	if err := resultStore.SaveResult(result); err != nil {
+		log.Warn("Failed to save scan result to history: %v", err)
+	}
+}
+
 // saveResults saves a single scan result
 func saveResults(result *types.ScanResult) error {
+	saveToHistory(result)
+
 	// Ensure output directory exists
 	if err := os.MkdirAll(cfg.OutputDir, 0755); err != nil {
 		return fmt.Errorf("failed to create output directory: %w", err)
@@ -509,6 +1193,11 @@ func saveResults(result *types.ScanResult) error {
 
 	// Generate filename
 	timestamp := time.Now().Format("20060102-150405")
+
+	if cfg.Format == "json" || cfg.Format == "sarif" || cfg.Format == "spdx" {
+		return saveReport(result, timestamp)
+	}
+
 	filename := fmt.Sprintf("confused-scan-%s-%s.json", result.Target, timestamp)
 	filepath := filepath.Join(cfg.OutputDir, filename)
 
@@ -527,8 +1216,37 @@ func saveResults(result *types.ScanResult) error {
 	return nil
 }
 
+// saveReport renders result through the pkg/report subsystem and writes it
+// to the output directory using the format's conventional file extension.
+func saveReport(result *types.ScanResult, timestamp string) error {
+	reporter, err := report.ForFormat(cfg.Format)
+	if err != nil {
+		return err
+	}
+
+	filename := fmt.Sprintf("confused-scan-%s-%s.%s", result.Target, timestamp, cfg.Format)
+	filepath := filepath.Join(cfg.OutputDir, filename)
+
+	file, err := os.Create(filepath)
+	if err != nil {
+		return fmt.Errorf("failed to create report file: %w", err)
+	}
+	defer file.Close()
+
+	if err := reporter.Write(file, result); err != nil {
+		return fmt.Errorf("failed to write %s report: %w", cfg.Format, err)
+	}
+
+	log.Info("Results saved to: %s", filepath)
+	return nil
+}
+
 // saveScanResults saves multiple scan results
 func saveScanResults(results []types.ScanResult) error {
+	for i := range results {
+		saveToHistory(&results[i])
+	}
+
 	// Ensure output directory exists
 	if err := os.MkdirAll(cfg.OutputDir, 0755); err != nil {
 		return fmt.Errorf("failed to create output directory: %w", err)
@@ -536,6 +1254,15 @@ func saveScanResults(results []types.ScanResult) error {
 
 	// Generate filename
 	timestamp := time.Now().Format("20060102-150405")
+
+	if cfg.Format == "json" || cfg.Format == "sarif" || cfg.Format == "spdx" {
+		for i := range results {
+			if err := saveReport(&results[i], timestamp); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
 	filename := fmt.Sprintf("confused-results-%s.json", timestamp)
 	filepath := filepath.Join(cfg.OutputDir, filename)
 
@@ -569,12 +1296,57 @@ func saveScanResults(results []types.ScanResult) error {
 	return nil
 }
 
+// newRegistrySet builds the registries.Set configured via --registry and
+// --internal-registry, so every command can point its resolvers at private
+// mirrors instead of assuming each ecosystem's public default.
+func newRegistrySet() (*registries.Set, error) {
+	set, err := registries.NewSet(cfg.Registries, cfg.InternalRegistries)
+	if err != nil {
+		return nil, err
+	}
+	set.AddEndpoints(cfg.RegistryConfig)
+	return set, nil
+}
+
+// newRateLimiter builds a shared token-bucket limiter sized by cfg.RateLimit
+// (requests per second), so every resolver/client a command wires up respects
+// the same global QPS ceiling against package registries.
+func newRateLimiter() *rate.Limiter {
+	return rate.NewLimiter(rate.Limit(cfg.RateLimit), cfg.RateLimit)
+}
+
+// newCacheStore builds the incremental scan cache for cmd, honoring its
+// --cache-dir/--cache-ttl/--no-cache flags (falling back to the
+// cache_dir/cache_ttl/no_cache config values), so a GitHub client can skip
+// downloading and re-resolving dependency blobs it's already seen.
+func newCacheStore(cmd *cobra.Command) (cache.Store, time.Duration, error) {
+	cacheDir, _ := cmd.Flags().GetString("cache-dir")
+	if cacheDir == "" {
+		cacheDir = cfg.CacheDir
+	}
+	cacheTTL, _ := cmd.Flags().GetInt("cache-ttl")
+	if cacheTTL == 0 {
+		cacheTTL = cfg.CacheTTL
+	}
+	noCache, _ := cmd.Flags().GetBool("no-cache")
+	noCache = noCache || cfg.NoCache
+
+	if noCache {
+		return cache.NewNoopStore(), time.Duration(cacheTTL) * time.Second, nil
+	}
+	store, err := cache.NewBoltStore(cacheDir)
+	if err != nil {
+		return nil, 0, err
+	}
+	return store, time.Duration(cacheTTL) * time.Second, nil
+}
+
 // removeSafe removes known-safe package names from the slice
 func removeSafe(packages []string, safeSpaces []string) []string {
 	if len(safeSpaces) == 0 {
 		return packages
 	}
-	
+
 	retSlice := []string{}
 	for _, pkg := range packages {
 		ignored := false
@@ -621,11 +1393,14 @@ func readTargetFile(filename string) ([]string, error) {
 }
 
 // processWebTargetsWithWorkers processes multiple web targets using a worker pool
-func processWebTargetsWithWorkers(scanner *web.Scanner, targets []string, languages []string, deep bool, maxDepth int, workers int) ([]*types.ScanResult, error) {
+func processWebTargetsWithWorkers(scanner *web.Scanner, targets []string, languages []string, deep bool, maxDepth int, workers int, prog progress.Progress) ([]*types.ScanResult, error) {
 	var allResults []*types.ScanResult
 	var mu sync.Mutex
 	var wg sync.WaitGroup
 
+	prog.Start(len(targets))
+	defer prog.Finish()
+
 	// Create channels for work distribution
 	targetChan := make(chan string, len(targets))
 	resultChan := make(chan []*types.ScanResult, len(targets))
@@ -633,16 +1408,34 @@ func processWebTargetsWithWorkers(scanner *web.Scanner, targets []string, langua
 	// Start workers
 	for i := 0; i < workers; i++ {
 		wg.Add(1)
+		workerID := i
 		go func() {
 			defer wg.Done()
+			workerLog := log.WithFields(map[string]interface{}{"worker_id": workerID})
 			for target := range targetChan {
-				log.Info("Scanning target: %s", target)
+				targetLog := workerLog.WithFields(map[string]interface{}{"target": target})
+				targetLog.Info("Scanning target: %s", target)
+				start := time.Now()
 				results, err := scanner.ScanTarget(target, languages, deep, maxDepth)
 				if err != nil {
-					log.Warn("Failed to scan target %s: %v", target, err)
+					targetLog.Warn("Failed to scan target %s: %v", target, err)
+					prog.Advance(target)
 					resultChan <- []*types.ScanResult{}
 					continue
 				}
+				vulnerableCount, safeCount := 0, 0
+				for _, result := range results {
+					vulnerableCount += len(result.Vulnerable)
+					safeCount += len(result.Safe)
+				}
+				targetLog.WithFields(map[string]interface{}{
+					"duration_ms":         time.Since(start).Milliseconds(),
+					"packages_vulnerable": vulnerableCount,
+				}).Info("Finished scanning target")
+				if counter, ok := prog.(progress.CountingProgress); ok {
+					counter.AddCounts(vulnerableCount, safeCount)
+				}
+				prog.Advance(target)
 				resultChan <- results
 			}
 		}()
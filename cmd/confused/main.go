@@ -1,27 +1,31 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"os"
+	"os/signal"
+	"syscall"
 
-	"github.com/h0tak88r/confused2/pkg/config"
-	"github.com/h0tak88r/confused2/pkg/logger"
+	"github.com/h0tak88r/confused/pkg/config"
+	"github.com/h0tak88r/confused/pkg/logger"
 	"github.com/spf13/cobra"
 )
 
 var (
-	cfg    *config.Config
-	log    *logger.Logger
-	version = "2.2.0"
-	buildDate = "2025-10-24"
+	cfg        *config.Config
+	log        *logger.Logger
+	configFile string
+	version    = "2.2.0"
+	buildDate  = "2025-10-24"
 )
 
 func main() {
 	var err error
-	
+
 	// Initialize config
 	cfg = config.Default()
-	
+
 	// Initialize logger
 	log, err = logger.New(logger.INFO, cfg.Verbose, "")
 	if err != nil {
@@ -32,9 +36,14 @@ func main() {
 
 	// Setup CLI
 	rootCmd := setupRootCommand()
-	
+
+	// Cancel the root context on SIGINT/SIGTERM so in-flight scans stop
+	// probing registries instead of running to completion.
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
 	// Execute the root command
-	if err := rootCmd.Execute(); err != nil {
+	if err := rootCmd.ExecuteContext(ctx); err != nil {
 		log.Error("Command execution failed: %v", err)
 		os.Exit(1)
 	}
@@ -46,40 +55,54 @@ func setupRootCommand() *cobra.Command {
 		Short: "Advanced Dependency Confusion Scanner",
 		Long: `Confused is an advanced dependency confusion scanner that can:
 - Scan local dependency files
-- Scan GitHub repositories and organizations
+- Scan GitHub, GitLab, Bitbucket, and Gitea repositories and organizations
 - Discover dependency files via web scanning
 - Support multiple package managers concurrently
 - Generate comprehensive reports`,
 		Version: fmt.Sprintf("%s (built %s)", version, buildDate),
 		PersistentPreRun: func(cmd *cobra.Command, args []string) {
 			// Load configuration
-			config.Load(cfg)
-			
+			config.Load(cfg, configFile)
+
 			// Setup logger with new settings
 			log.SetVerbose(cfg.Verbose)
 			if cfg.Verbose {
 				log.SetLevel(logger.DEBUG)
 			}
+			log.SetFormat(logger.Format(cfg.LogFormat))
 		},
 	}
 
 	// Global flags
+	rootCmd.PersistentFlags().StringVar(&configFile, "config", "", "Path to a confused.yaml config file (default: searches ./confused.yaml, $HOME/.confused, /etc/confused)")
 	rootCmd.PersistentFlags().BoolVarP(&cfg.Verbose, "verbose", "v", false, "Verbose output")
 	rootCmd.PersistentFlags().StringVarP(&cfg.Output, "output", "o", "", "Output file path")
-	rootCmd.PersistentFlags().StringVarP(&cfg.Format, "format", "f", "text", "Output format (text, json, html)")
+	rootCmd.PersistentFlags().StringVarP(&cfg.Format, "format", "f", "text", "Output format (text, json, html, sarif, spdx)")
+	rootCmd.PersistentFlags().StringVar(&cfg.LogFormat, "log-format", "text", "Log output format (text, json, logfmt)")
+	rootCmd.PersistentFlags().BoolVar(&cfg.NoProgress, "no-progress", false, "Disable the live progress bar for org/web scans")
 	rootCmd.PersistentFlags().IntVarP(&cfg.Workers, "workers", "w", 10, "Number of concurrent workers")
 	rootCmd.PersistentFlags().IntVar(&cfg.Timeout, "timeout", 30, "Request timeout in seconds")
 	rootCmd.PersistentFlags().StringSliceVar(&cfg.SafeSpaces, "safe-spaces", []string{}, "Known-safe namespaces (supports wildcards)")
+	rootCmd.PersistentFlags().StringArrayVar(&cfg.Registries, "registry", []string{}, "Public registry mirror for an ecosystem: ecosystem=url[|Header-Name: value] (repeatable)")
+	rootCmd.PersistentFlags().StringArrayVar(&cfg.InternalRegistries, "internal-registry", []string{}, "Internal registry for an ecosystem: ecosystem=url[|Header-Name: value] (repeatable)")
 	rootCmd.PersistentFlags().StringVar(&cfg.OutputDir, "output-dir", "./results", "Output directory for results")
 	rootCmd.PersistentFlags().BoolVar(&cfg.SaveResults, "save", true, "Save results to files")
 	rootCmd.PersistentFlags().StringVar(&cfg.GitHubToken, "github-token", "", "GitHub API token")
 	rootCmd.PersistentFlags().StringVar(&cfg.UserAgent, "user-agent", "Confused-DepConfusion-Scanner/2.0", "User agent for HTTP requests")
+	rootCmd.PersistentFlags().StringVar(&cfg.CacheDir, "cache-dir", "./.confused-cache", "Directory for the on-disk incremental scan cache")
+	rootCmd.PersistentFlags().IntVar(&cfg.CacheTTL, "cache-ttl", 3600, "Registry availability cache TTL in seconds")
+	rootCmd.PersistentFlags().BoolVar(&cfg.NoCache, "no-cache", false, "Disable the incremental scan cache")
 
 	// Add subcommands
 	rootCmd.AddCommand(createScanCommand())
+	rootCmd.AddCommand(createMonorepoCommand())
 	rootCmd.AddCommand(createGitHubCommand())
+	rootCmd.AddCommand(createGitLabCommand())
+	rootCmd.AddCommand(createBitbucketCommand())
+	rootCmd.AddCommand(createGiteaCommand())
 	rootCmd.AddCommand(createWebCommand())
 	rootCmd.AddCommand(createConfigCommand())
+	rootCmd.AddCommand(createHistoryCommand())
 
 	return rootCmd
 }
@@ -0,0 +1,103 @@
+package cache
+
+import (
+	"encoding/binary"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// defaultCacheDir is used when --cache-dir is empty.
+const defaultCacheDir = "./.confused-cache"
+
+// cacheBucket is the single bbolt bucket every cached entry lives in -
+// blob resolutions and registry lookups share the keyspace via their
+// BlobKey/RegistryKey prefixes.
+var cacheBucket = []byte("cache")
+
+// BoltStore is the on-disk default Store backend: a single bbolt file
+// holding every cached entry.
+type BoltStore struct {
+	db *bolt.DB
+}
+
+// NewBoltStore opens (creating if necessary) a bbolt database under dir.
+func NewBoltStore(dir string) (*BoltStore, error) {
+	if dir == "" {
+		dir = defaultCacheDir
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create cache directory %s: %w", dir, err)
+	}
+
+	db, err := bolt.Open(filepath.Join(dir, "cache.db"), 0644, &bolt.Options{Timeout: 5 * time.Second})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open cache database: %w", err)
+	}
+
+	if err := db.Update(func(tx *bolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists(cacheBucket)
+		return err
+	}); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to initialize cache bucket: %w", err)
+	}
+
+	return &BoltStore{db: db}, nil
+}
+
+// Get implements Store.
+func (b *BoltStore) Get(key string) ([]byte, bool) {
+	var raw []byte
+	_ = b.db.View(func(tx *bolt.Tx) error {
+		if v := tx.Bucket(cacheBucket).Get([]byte(key)); v != nil {
+			raw = append([]byte(nil), v...)
+		}
+		return nil
+	})
+	if raw == nil {
+		return nil, false
+	}
+	value, expiresAt := decodeEntry(raw)
+	if expiresAt != 0 && time.Now().Unix() > expiresAt {
+		return nil, false
+	}
+	return value, true
+}
+
+// Set implements Store.
+func (b *BoltStore) Set(key string, value []byte, ttl time.Duration) error {
+	return b.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(cacheBucket).Put([]byte(key), encodeEntry(value, ttl))
+	})
+}
+
+// Close implements Store.
+func (b *BoltStore) Close() error {
+	return b.db.Close()
+}
+
+// encodeEntry prepends an 8-byte big-endian unix expiry (0 meaning never)
+// to value, so a single bucket Get can recover both in one read.
+func encodeEntry(value []byte, ttl time.Duration) []byte {
+	var expiresAt int64
+	if ttl > 0 {
+		expiresAt = time.Now().Add(ttl).Unix()
+	}
+	buf := make([]byte, 8+len(value))
+	binary.BigEndian.PutUint64(buf[:8], uint64(expiresAt))
+	copy(buf[8:], value)
+	return buf
+}
+
+// decodeEntry splits a stored entry back into its value and expiry (0
+// meaning never), returning a nil value for a corrupt/too-short entry.
+func decodeEntry(raw []byte) (value []byte, expiresAt int64) {
+	if len(raw) < 8 {
+		return nil, 0
+	}
+	return raw[8:], int64(binary.BigEndian.Uint64(raw[:8]))
+}
@@ -0,0 +1,107 @@
+// Package cache memoizes the expensive parts of a scan: the per-blob
+// package resolution a dependency file goes through (ReadPackagesFromFile +
+// ResolveWithContext), and the per-package registry availability checks
+// those resolutions are built from. A deep scan across a repo's branches,
+// or an org scan across sibling repos that vendor the same dependency file,
+// would otherwise re-download and re-resolve (or re-hit the public
+// registry for) the exact same blob/package every time.
+package cache
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/h0tak88r/confused/internal/types"
+	"github.com/h0tak88r/confused/pkg/config"
+)
+
+// Store is a pluggable cache backend keyed by an opaque string key. Get
+// reports a miss both when key was never set and when it was set with a
+// ttl that has since elapsed.
+type Store interface {
+	Get(key string) ([]byte, bool)
+	Set(key string, value []byte, ttl time.Duration) error
+	Close() error
+}
+
+// BlobEntry is what a resolved dependency blob caches: the vulnerable
+// package names PackagesNotInPublic reported, and (when the resolver
+// implements EnhancedPackageResolver) the richer per-package details used
+// by --format json/sarif.
+type BlobEntry struct {
+	Vulnerable []string              `json:"vulnerable"`
+	Details    []types.PackageDetail `json:"details,omitempty"`
+}
+
+// BlobKey returns the cache key for a resolved dependency blob, scoped by
+// provider (e.g. "github"), language, and the blob's content SHA so an
+// unchanged file is never re-resolved even if it's renamed or moved.
+func BlobKey(provider, language, blobSHA string) string {
+	return fmt.Sprintf("blob/%s/%s/%s", provider, language, blobSHA)
+}
+
+// RegistryKey returns the cache key for a single
+// ecosystem+package-name+version registry availability lookup. version is
+// part of the key, not just pkgname, because availability reflects both
+// namespace presence and whether that specific version is published
+// (CheckVersionAgainstFetch) - two repos pinning the same package at
+// different versions must not share one cached answer.
+func RegistryKey(ecosystem, pkgname, version string) string {
+	return fmt.Sprintf("registry/%s/%s/%s", ecosystem, pkgname, version)
+}
+
+// GetBlob looks up the cached resolution for a dependency blob. ok is false
+// on a cache miss or a corrupt entry.
+func GetBlob(s Store, provider, language, blobSHA string) (entry *BlobEntry, ok bool) {
+	raw, found := s.Get(BlobKey(provider, language, blobSHA))
+	if !found {
+		return nil, false
+	}
+	var e BlobEntry
+	if err := json.Unmarshal(raw, &e); err != nil {
+		return nil, false
+	}
+	return &e, true
+}
+
+// SetBlob caches entry for a dependency blob. Blob resolutions are stored
+// without their own expiry - a blob's own content never changes once
+// identified by its SHA, so only the registry lookups it was built from
+// need a TTL.
+func SetBlob(s Store, provider, language, blobSHA string, entry *BlobEntry) error {
+	raw, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("failed to marshal cache entry: %w", err)
+	}
+	return s.Set(BlobKey(provider, language, blobSHA), raw, 0)
+}
+
+// GetRegistryAvailability looks up a cached (ecosystem, pkgname, version)
+// registry availability check. ok is false on a cache miss or expiry.
+func GetRegistryAvailability(s Store, ecosystem, pkgname, version string) (available bool, ok bool) {
+	raw, found := s.Get(RegistryKey(ecosystem, pkgname, version))
+	if !found || len(raw) == 0 {
+		return false, false
+	}
+	return raw[0] == 1, true
+}
+
+// SetRegistryAvailability caches a (ecosystem, pkgname, version) registry
+// availability check, expiring it after ttl (zero means never).
+func SetRegistryAvailability(s Store, ecosystem, pkgname, version string, available bool, ttl time.Duration) error {
+	value := byte(0)
+	if available {
+		value = 1
+	}
+	return s.Set(RegistryKey(ecosystem, pkgname, version), []byte{value}, ttl)
+}
+
+// New returns the Store configured by cfg: a NoopStore when --no-cache is
+// set, otherwise a bbolt-backed store rooted at --cache-dir.
+func New(cfg *config.Config) (Store, error) {
+	if cfg.NoCache {
+		return NewNoopStore(), nil
+	}
+	return NewBoltStore(cfg.CacheDir)
+}
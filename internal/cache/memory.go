@@ -0,0 +1,80 @@
+package cache
+
+import (
+	"sync"
+	"time"
+)
+
+// MemoryStore is an in-process Store backed by a mutex-guarded map. It is
+// not persisted across runs; useful for tests and for callers that want
+// caching within a single process without touching disk.
+type MemoryStore struct {
+	mu      sync.Mutex
+	entries map[string]memoryEntry
+}
+
+type memoryEntry struct {
+	value     []byte
+	expiresAt int64 // unix seconds, 0 meaning never
+}
+
+// NewMemoryStore returns an empty MemoryStore.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{entries: make(map[string]memoryEntry)}
+}
+
+// Get implements Store.
+func (m *MemoryStore) Get(key string) ([]byte, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	e, ok := m.entries[key]
+	if !ok {
+		return nil, false
+	}
+	if e.expiresAt != 0 && time.Now().Unix() > e.expiresAt {
+		delete(m.entries, key)
+		return nil, false
+	}
+	return e.value, true
+}
+
+// Set implements Store.
+func (m *MemoryStore) Set(key string, value []byte, ttl time.Duration) error {
+	var expiresAt int64
+	if ttl > 0 {
+		expiresAt = time.Now().Add(ttl).Unix()
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.entries[key] = memoryEntry{value: value, expiresAt: expiresAt}
+	return nil
+}
+
+// Close implements Store.
+func (m *MemoryStore) Close() error {
+	return nil
+}
+
+// NoopStore is the Store used for --no-cache: every Get is a miss and
+// every Set is discarded, so callers don't need a nil check.
+type NoopStore struct{}
+
+// NewNoopStore returns a Store that never caches anything.
+func NewNoopStore() *NoopStore {
+	return &NoopStore{}
+}
+
+// Get implements Store.
+func (n *NoopStore) Get(key string) ([]byte, bool) {
+	return nil, false
+}
+
+// Set implements Store.
+func (n *NoopStore) Set(key string, value []byte, ttl time.Duration) error {
+	return nil
+}
+
+// Close implements Store.
+func (n *NoopStore) Close() error {
+	return nil
+}
@@ -0,0 +1,294 @@
+// Package remediation turns a read-only confused scan into a closed-loop
+// defense: filing a GitHub issue that documents each confusable package
+// name a scan found, and/or opening a PR against the user's own
+// registry-mirror repo that reserves those names with placeholder package
+// manifests before an attacker can register them.
+package remediation
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/google/go-github/v58/github"
+	"github.com/h0tak88r/confused/internal/types"
+	"github.com/h0tak88r/confused/pkg/logger"
+)
+
+// Mode is one action --remediate can trigger for a vulnerable scan.
+type Mode string
+
+const (
+	// ModeIssue files a GitHub issue on the scanned repo summarizing the
+	// confusable packages.
+	ModeIssue Mode = "issue"
+	// ModeReserve stages a branch in --remediation-repo with placeholder
+	// manifests reserving each vulnerable name, without opening a PR - useful
+	// to review the diff before it goes out.
+	ModeReserve Mode = "reserve"
+	// ModePR does everything ModeReserve does, then opens the PR.
+	ModePR Mode = "pr"
+)
+
+// vulnerability is one confusable package name flattened out of a
+// ScanResult, carrying the file/branch/ecosystem context the issue and PR
+// templates need.
+type vulnerability struct {
+	Package   string
+	Ecosystem string
+	FilePath  string
+	Branch    string
+}
+
+// reservableEcosystems lists the resolver languages where an arbitrary user
+// can claim an unclaimed name by publishing under it - unlike composer
+// (vendor-namespaced) or mvn (reverse-DNS groupId, generally domain
+// verified), so only these are worth staging a placeholder manifest for.
+var reservableEcosystems = map[string]bool{
+	"npm":      true,
+	"pip":      true,
+	"rubygems": true,
+}
+
+// packageNameGrammar is the real name grammar for each reservableEcosystems
+// entry: npm allows an optional @scope/ prefix alongside its own charset,
+// while pip (PEP 503) and rubygems are flat lowercase-letter/digit/./-/_
+// names. v.Package comes verbatim from a scanned repo's dependency manifest
+// - an arbitrary attacker-controlled string if they can open a PR against
+// any repo confused scans - and is used as a path segment when staging a
+// placeholder manifest in the (trusted) --remediation-repo, so it must be
+// validated against this grammar before placeholderManifest ever sees it;
+// otherwise a hostile manifest entry like "../../.github/workflows/ci" could
+// write outside the intended reservation directory.
+var packageNameGrammar = map[string]*regexp.Regexp{
+	"npm":      regexp.MustCompile(`^(@[a-z0-9._-]+/)?[a-z0-9._-]+$`),
+	"pip":      regexp.MustCompile(`^[a-zA-Z0-9._-]+$`),
+	"rubygems": regexp.MustCompile(`^[a-zA-Z0-9._-]+$`),
+}
+
+// isReservableName reports whether v.Package matches the real name grammar
+// for its ecosystem, so it's safe to use as a path segment.
+func isReservableName(v vulnerability) bool {
+	grammar, ok := packageNameGrammar[v.Ecosystem]
+	if !ok {
+		return false
+	}
+	return grammar.MatchString(v.Package)
+}
+
+// Remediator files issues and reservation PRs using the go-github client
+// already authenticated by pkg/github.Client.
+type Remediator struct {
+	client *github.Client
+	logger *logger.Logger
+}
+
+// New constructs a Remediator around client, the same authenticated
+// go-github client pkg/github.Client uses for scanning (via
+// (*pkg/github.Client).RawClient), so remediation doesn't need its own
+// token/oauth2 setup.
+func New(client *github.Client, log *logger.Logger) *Remediator {
+	return &Remediator{client: client, logger: log}
+}
+
+// Run gates FileIssue/ReservePackageNames behind modes (the parsed
+// --remediate flag values), doing nothing if results has no vulnerable
+// packages. owner/repoName identify the scanned repo (where an issue is
+// filed); remediationRepo is the "owner/repo" reservation target required
+// by ModeReserve/ModePR.
+func (r *Remediator) Run(ctx context.Context, owner, repoName string, modes []string, remediationRepo string, results []*types.ScanResult) error {
+	vulns := flatten(results)
+	if len(vulns) == 0 {
+		return nil
+	}
+
+	for _, mode := range modes {
+		switch Mode(mode) {
+		case ModeIssue:
+			if _, err := r.FileIssue(ctx, owner, repoName, vulns); err != nil {
+				return err
+			}
+		case ModeReserve, ModePR:
+			if remediationRepo == "" {
+				return fmt.Errorf("--remediation-repo must be set for --remediate=%s", mode)
+			}
+			parts := strings.Split(remediationRepo, "/")
+			if len(parts) != 2 {
+				return fmt.Errorf("invalid --remediation-repo %q (expected owner/repo)", remediationRepo)
+			}
+			if _, err := r.ReservePackageNames(ctx, parts[0], parts[1], vulns, Mode(mode) == ModePR); err != nil {
+				return err
+			}
+		default:
+			return fmt.Errorf("unknown --remediate mode %q (expected issue, reserve, or pr)", mode)
+		}
+	}
+	return nil
+}
+
+// FileIssue opens a GitHub issue on owner/repoName summarizing vulns in a
+// Markdown table, suggesting the scanned repo's own owner reserve the names.
+func (r *Remediator) FileIssue(ctx context.Context, owner, repoName string, vulns []vulnerability) (*github.Issue, error) {
+	req := &github.IssueRequest{
+		Title: github.String(fmt.Sprintf("confused: %d potentially confusable package name(s) found", len(vulns))),
+		Body:  github.String(issueBody(vulns, owner)),
+	}
+	issue, _, err := r.client.Issues.Create(ctx, owner, repoName, req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to file remediation issue on %s/%s: %w", owner, repoName, err)
+	}
+	r.logger.Info("Filed remediation issue %s", issue.GetHTMLURL())
+	return issue, nil
+}
+
+// ReservePackageNames stages a branch in owner/repoName (the
+// --remediation-repo) containing a placeholder manifest per reservable
+// vulnerability, then opens a PR against the repo's default branch if
+// openPR is set.
+func (r *Remediator) ReservePackageNames(ctx context.Context, owner, repoName string, vulns []vulnerability, openPR bool) (*github.PullRequest, error) {
+	var reservable []vulnerability
+	for _, v := range vulns {
+		if !reservableEcosystems[v.Ecosystem] {
+			r.logger.Debug("Skipping reservation for %s: %s has no open user namespace to squat", v.Package, v.Ecosystem)
+			continue
+		}
+		if !isReservableName(v) {
+			r.logger.Warn("Skipping reservation for %q: not a valid %s package name", v.Package, v.Ecosystem)
+			continue
+		}
+		reservable = append(reservable, v)
+	}
+	if len(reservable) == 0 {
+		return nil, nil
+	}
+
+	repo, _, err := r.client.Repositories.Get(ctx, owner, repoName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get remediation repo %s/%s: %w", owner, repoName, err)
+	}
+	baseBranch := repo.GetDefaultBranch()
+
+	baseRef, _, err := r.client.Git.GetRef(ctx, owner, repoName, "refs/heads/"+baseBranch)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get base ref %s for %s/%s: %w", baseBranch, owner, repoName, err)
+	}
+
+	branchName := fmt.Sprintf("confused/reserve-%d", time.Now().Unix())
+	newRef := &github.Reference{
+		Ref:    github.String("refs/heads/" + branchName),
+		Object: baseRef.Object,
+	}
+	if _, _, err := r.client.Git.CreateRef(ctx, owner, repoName, newRef); err != nil {
+		return nil, fmt.Errorf("failed to create branch %s in %s/%s: %w", branchName, owner, repoName, err)
+	}
+
+	for _, v := range reservable {
+		path, content := placeholderManifest(v)
+		opts := &github.RepositoryContentFileOptions{
+			Message: github.String(fmt.Sprintf("reserve %s on %s", v.Package, v.Ecosystem)),
+			Content: []byte(content),
+			Branch:  github.String(branchName),
+		}
+		if _, _, err := r.client.Repositories.CreateFile(ctx, owner, repoName, path, opts); err != nil {
+			return nil, fmt.Errorf("failed to stage placeholder manifest for %s: %w", v.Package, err)
+		}
+	}
+	r.logger.Info("Staged reservation branch %s in %s/%s (%d package(s))", branchName, owner, repoName, len(reservable))
+
+	if !openPR {
+		return nil, nil
+	}
+
+	pr, _, err := r.client.PullRequests.Create(ctx, owner, repoName, &github.NewPullRequest{
+		Title: github.String("confused: reserve confusable package names"),
+		Head:  github.String(branchName),
+		Base:  github.String(baseBranch),
+		Body:  github.String(prBody(reservable)),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open reservation PR against %s/%s: %w", owner, repoName, err)
+	}
+	r.logger.Info("Opened reservation PR %s", pr.GetHTMLURL())
+	return pr, nil
+}
+
+// flatten pulls every vulnerable package name out of results into a
+// vulnerability carrying the file/branch/ecosystem it was found in.
+func flatten(results []*types.ScanResult) []vulnerability {
+	var out []vulnerability
+	for _, res := range results {
+		filePath, _ := res.Metadata["file_path"].(string)
+		branch, _ := res.Metadata["branch"].(string)
+		for _, pkg := range res.Vulnerable {
+			out = append(out, vulnerability{
+				Package:   pkg,
+				Ecosystem: res.Language,
+				FilePath:  filePath,
+				Branch:    branch,
+			})
+		}
+	}
+	return out
+}
+
+// issueBody templates the Markdown body of a FileIssue issue.
+func issueBody(vulns []vulnerability, suggestedOwner string) string {
+	var b strings.Builder
+	b.WriteString("confused found package names referenced below that aren't published in their public registry. ")
+	b.WriteString("An attacker who registers one of these names could have it pulled in as a dependency (a dependency confusion attack).\n\n")
+	b.WriteString("| Package | Ecosystem | File | Branch |\n")
+	b.WriteString("|---|---|---|---|\n")
+	for _, v := range vulns {
+		b.WriteString(fmt.Sprintf("| `%s` | %s | `%s` | `%s` |\n", v.Package, v.Ecosystem, v.FilePath, v.Branch))
+	}
+	b.WriteString(fmt.Sprintf("\nSuggested fix: publish a placeholder release of each package under `%s` (or your organization's own namespace) before an attacker does. `--remediate=pr` can open that PR automatically.\n", suggestedOwner))
+	return b.String()
+}
+
+// prBody templates the Markdown body of a ReservePackageNames PR.
+func prBody(vulns []vulnerability) string {
+	var b strings.Builder
+	b.WriteString("Reserves the following package names a confused scan found unpublished, so they can't be claimed for a dependency confusion attack:\n\n")
+	for _, v := range vulns {
+		b.WriteString(fmt.Sprintf("- `%s` (%s), referenced from `%s` on branch `%s`\n", v.Package, v.Ecosystem, v.FilePath, v.Branch))
+	}
+	return b.String()
+}
+
+// placeholderManifest returns the file path and contents of a minimal
+// manifest that claims v.Package's name on its ecosystem's registry without
+// publishing any real code.
+func placeholderManifest(v vulnerability) (path string, content string) {
+	switch v.Ecosystem {
+	case "npm":
+		return v.Package + "/package.json", fmt.Sprintf(`{
+  "name": %q,
+  "version": "0.0.0-reserved",
+  "description": "Reserved by confused to prevent a dependency confusion attack.",
+  "license": "UNLICENSED"
+}
+`, v.Package)
+	case "pip":
+		return v.Package + "/setup.py", fmt.Sprintf(`from setuptools import setup
+
+setup(
+    name=%q,
+    version="0.0.0.reserved",
+    description="Reserved by confused to prevent a dependency confusion attack.",
+)
+`, v.Package)
+	case "rubygems":
+		return v.Package + "/" + v.Package + ".gemspec", fmt.Sprintf(`Gem::Specification.new do |s|
+  s.name        = %q
+  s.version     = "0.0.0.reserved"
+  s.summary     = "Reserved by confused to prevent a dependency confusion attack."
+  s.authors     = ["confused"]
+  s.files       = []
+end
+`, v.Package)
+	default:
+		return v.Package + "/RESERVED", "Reserved by confused to prevent a dependency confusion attack.\n"
+	}
+}
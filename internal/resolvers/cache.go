@@ -0,0 +1,39 @@
+package resolvers
+
+import (
+	"time"
+
+	"github.com/h0tak88r/confused/internal/cache"
+)
+
+// CacheAware is implemented by resolvers that can be pointed at a shared
+// cache.Store so repeated registry availability checks for the same
+// ecosystem+package (across branches of a deep scan, or sibling repos in
+// an org scan) are served from cache instead of re-hitting the registry.
+type CacheAware interface {
+	SetCache(store cache.Store, ttl time.Duration)
+}
+
+// cachedAvailability looks up a previously-cached public-registry
+// availability result for pkgname at version under ecosystem. version is
+// part of the lookup, not just pkgname, because availability reflects
+// whether that specific requested version is published, not just whether
+// the package namespace exists - otherwise two sibling repos pinning the
+// same package at different versions would share one wrong answer. A nil
+// store (the resolver was never wired up via SetCache) is always a miss.
+func cachedAvailability(store cache.Store, ecosystem, pkgname, version string) (available bool, ok bool) {
+	if store == nil {
+		return false, false
+	}
+	return cache.GetRegistryAvailability(store, ecosystem, pkgname, version)
+}
+
+// setCachedAvailability caches a public-registry availability result for
+// pkgname at version under ecosystem. A nil store is a no-op; a write
+// failure is non-fatal, since the cache is strictly an optimization.
+func setCachedAvailability(store cache.Store, ttl time.Duration, ecosystem, pkgname, version string, available bool) {
+	if store == nil {
+		return
+	}
+	_ = cache.SetRegistryAvailability(store, ecosystem, pkgname, version, available, ttl)
+}
@@ -0,0 +1,403 @@
+package resolvers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/h0tak88r/confused/internal/cache"
+	"github.com/h0tak88r/confused/internal/types"
+	"github.com/h0tak88r/confused/pkg/logger"
+	"github.com/h0tak88r/confused/pkg/registries"
+	"golang.org/x/time/rate"
+)
+
+// defaultCratesRegistry is crates.io, used when no --registry/
+// --internal-registry flag overrides the "cargo" ecosystem.
+const defaultCratesRegistry = "https://crates.io/api/v1/crates"
+
+// cargoDependencyHeaderPattern matches a Cargo.toml dependency table header,
+// e.g. "[dependencies]", "[dev-dependencies]", "[build-dependencies]", or
+// the target-scoped form "[target.'cfg(unix)'.dependencies]".
+var cargoDependencyHeaderPattern = regexp.MustCompile(`^\[.*depend.*\]$`)
+
+// cargoSimpleDepPattern matches a bare version requirement, e.g. `serde = "1.0"`.
+var cargoSimpleDepPattern = regexp.MustCompile(`^([A-Za-z0-9_-]+)\s*=\s*"([^"]*)"`)
+
+// cargoTableDepPattern matches an inline-table requirement with an explicit
+// version key, e.g. `tokio = { version = "1", features = ["full"] }`.
+var cargoTableDepPattern = regexp.MustCompile(`^([A-Za-z0-9_-]+)\s*=\s*\{.*version\s*=\s*"([^"]*)"`)
+
+// CargoPackage is a single crate requirement parsed from a Cargo.toml file.
+type CargoPackage struct {
+	Name    string
+	Version string
+}
+
+// CargoLookup represents a collection of Rust crates to be tested for dependency confusion.
+type CargoLookup struct {
+	Packages          []CargoPackage
+	Verbose           bool
+	ctx               context.Context
+	timeout           time.Duration
+	rateLimit         int
+	limiter           *rate.Limiter
+	registrySet       *registries.Set
+	log               *logger.Logger
+	workers           int
+	packageDetails    []types.PackageDetail
+	notAvailableCache []string
+	resolved          bool
+	cache             cache.Store
+	cacheTTL          time.Duration
+	httpClient        *http.Client
+	metrics           *Metrics
+}
+
+// NewCargoLookup constructs a `CargoLookup` struct and returns it.
+func NewCargoLookup(verbose bool) types.PackageResolver {
+	return &CargoLookup{
+		Packages:       []CargoPackage{},
+		Verbose:        verbose,
+		ctx:            context.Background(),
+		timeout:        30 * time.Second,
+		rateLimit:      100,
+		workers:        defaultWorkers,
+		packageDetails: []types.PackageDetail{},
+	}
+}
+
+// SetCache wires store/ttl in so isAvailableInPublic checks are served
+// from cache before falling through to a live registry probe.
+func (c *CargoLookup) SetCache(store cache.Store, ttl time.Duration) {
+	c.cache = store
+	c.cacheTTL = ttl
+}
+
+// SetHTTPClient points this resolver at a shared, instrumented *http.Client
+// (see ResolveAll) instead of building one per registry via Registry.Client.
+func (c *CargoLookup) SetHTTPClient(client *http.Client) {
+	c.httpClient = client
+}
+
+// SetMetrics points this resolver at a shared Metrics instance so its
+// registry checks are counted alongside every other resolver ResolveAll runs.
+func (c *CargoLookup) SetMetrics(m *Metrics) {
+	c.metrics = m
+}
+
+// ReadPackagesFromFile reads package information from a Cargo.toml file.
+//
+// Returns any errors encountered
+func (c *CargoLookup) ReadPackagesFromFile(filename string) error {
+	rawfile, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return err
+	}
+
+	if c.Verbose {
+		fmt.Print("Checking: filename: " + filename + "\n")
+	}
+
+	inDependencyTable := false
+	for _, l := range strings.Split(string(rawfile), "\n") {
+		l = strings.TrimSpace(l)
+		if l == "" || strings.HasPrefix(l, "#") {
+			continue
+		}
+
+		if strings.HasPrefix(l, "[") {
+			inDependencyTable = cargoDependencyHeaderPattern.MatchString(l)
+			continue
+		}
+		if !inDependencyTable {
+			continue
+		}
+
+		if m := cargoTableDepPattern.FindStringSubmatch(l); m != nil {
+			c.Packages = append(c.Packages, CargoPackage{Name: m[1], Version: m[2]})
+			continue
+		}
+		if m := cargoSimpleDepPattern.FindStringSubmatch(l); m != nil {
+			c.Packages = append(c.Packages, CargoPackage{Name: m[1], Version: m[2]})
+		}
+	}
+
+	return nil
+}
+
+// PackagesNotInPublic determines if a crate does not exist in the
+// configured public crates.io-compatible registry.
+//
+// Returns a slice of strings with any crates not in the public registry
+func (c *CargoLookup) PackagesNotInPublic() []string {
+	if c.resolved {
+		return c.notAvailableCache
+	}
+	notavail, _ := c.resolve(context.Background())
+	return notavail
+}
+
+// ResolveWithContext behaves like PackagesNotInPublic but aborts as soon as
+// ctx is cancelled and honors the rate limiter set via SetLimiter, so a
+// SIGINT during an org scan stops in-flight registry probes instead of
+// running to completion.
+func (c *CargoLookup) ResolveWithContext(ctx context.Context) error {
+	c.ctx = ctx
+	notavail, err := c.resolve(ctx)
+	if err != nil {
+		return err
+	}
+	c.notAvailableCache = notavail
+	c.resolved = true
+	return nil
+}
+
+// resolve dispatches an isConfused check per crate across c.workers
+// goroutines, rate-limited by c.limiter, returning the confused crates in
+// the same order as c.Packages.
+func (c *CargoLookup) resolve(ctx context.Context) ([]string, error) {
+	indices, err := resolveConcurrently(ctx, len(c.Packages), c.workers, func(ctx context.Context, i int) (bool, error) {
+		if err := waitForToken(ctx, c.limiter); err != nil {
+			return false, err
+		}
+		return c.isConfused(c.Packages[i]), nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	notavail := make([]string, 0, len(indices))
+	for _, i := range indices {
+		notavail = append(notavail, c.Packages[i].Name)
+	}
+	return notavail, nil
+}
+
+// SetLimiter points this resolver at a shared rate limiter.
+func (c *CargoLookup) SetLimiter(limiter *rate.Limiter) {
+	c.limiter = limiter
+}
+
+// SetWorkers points this resolver at the configured --workers concurrency
+// for its registry probes.
+func (c *CargoLookup) SetWorkers(workers int) {
+	if workers > 0 {
+		c.workers = workers
+	}
+}
+
+// SetRegistries points this resolver at the configured public/internal
+// crates.io-compatible registries, so a crate only counts as "public" if
+// it's found in one of the configured public registries (defaulting to
+// crates.io), and only counts as confused if it's also present in a
+// configured internal one.
+func (c *CargoLookup) SetRegistries(regs *registries.Set) {
+	c.registrySet = regs
+}
+
+// SetLogger points this resolver at a structured logger, so each registry
+// HTTP check emits one event record instead of an ad-hoc fmt.Printf line.
+func (c *CargoLookup) SetLogger(log *logger.Logger) {
+	c.log = log
+}
+
+// logCheck emits a structured "registry_check" event for a single HTTP
+// check. If no logger was wired via SetLogger, it falls back to printing a
+// human-readable line so the resolver still works when used standalone.
+func (c *CargoLookup) logCheck(reg registries.Registry, pkg CargoPackage, retry int, statusCode int, duration time.Duration, result string) {
+	if c.log == nil {
+		fmt.Printf(" [%s] %s (registry=%s status=%d retry=%d)\n", result, pkg.Name, reg.URL, statusCode, retry)
+		return
+	}
+	c.log.WithFields(map[string]interface{}{
+		"event":       "registry_check",
+		"registry":    reg.URL,
+		"package":     pkg.Name,
+		"version":     pkg.Version,
+		"status_code": statusCode,
+		"retry":       retry,
+		"duration_ms": duration.Milliseconds(),
+		"result":      result,
+	}).Debug("cargo registry check")
+}
+
+// isConfused reports whether pkg is absent from every configured public
+// crates registry and, when an internal allowlist was configured, present
+// (or expected, if no internal allowlist was configured) in one of those.
+func (c *CargoLookup) isConfused(pkg CargoPackage) bool {
+	if c.isAvailableInPublic(pkg) {
+		return false
+	}
+	if len(c.registrySet.Internal("cargo")) == 0 {
+		return true
+	}
+	return c.isAvailableInInternal(pkg)
+}
+
+// isAvailableInPublic determines if a crate exists in any of the
+// configured public registries (crates.io by default).
+//
+// Returns true if the crate exists in a public registry.
+func (c *CargoLookup) isAvailableInPublic(pkg CargoPackage) bool {
+	if available, ok := cachedAvailability(c.cache, "cargo", pkg.Name, pkg.Version); ok {
+		c.metrics.RecordCacheHit()
+		return available
+	}
+	available := false
+	for _, reg := range c.registrySet.Public("cargo", defaultCratesRegistry) {
+		if c.checkRegistry(reg, pkg) {
+			available = true
+			break
+		}
+	}
+	setCachedAvailability(c.cache, c.cacheTTL, "cargo", pkg.Name, pkg.Version, available)
+	return available
+}
+
+// isAvailableInInternal determines if a crate exists in any of the
+// registries configured as "internal" via --internal-registry.
+func (c *CargoLookup) isAvailableInInternal(pkg CargoPackage) bool {
+	for _, reg := range c.registrySet.Internal("cargo") {
+		if c.checkRegistry(reg, pkg) {
+			return true
+		}
+	}
+	return false
+}
+
+// checkRegistry determines if a crate exists in a single registry, via its
+// crates.io-compatible API (https://crates.io/api/v1/crates/<name>), which
+// 404s for a crate name that's never been published. 429/5xx are retried
+// with backoff centrally by doRegistryGET.
+func (c *CargoLookup) checkRegistry(reg registries.Registry, pkg CargoPackage) bool {
+	url := reg.URL + "/" + pkg.Name
+	resp, duration, retries, err := doRegistryGET(reg, url, c.timeout, c.httpClient, c.metrics)
+	if err != nil {
+		c.logCheck(reg, pkg, retries, 0, duration, fmt.Sprintf("request_error: %s", err))
+		return false
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		c.logCheck(reg, pkg, retries, resp.StatusCode, duration, "not_available")
+		return false
+	}
+	if matched, ok, _ := CheckVersionAgainstFetch(pkg.Version, func() ([]string, error) {
+		return fetchCargoVersions(reg, pkg.Name)
+	}); ok && !matched {
+		c.logCheck(reg, pkg, retries, resp.StatusCode, duration, "version_not_published")
+		return false
+	}
+	c.logCheck(reg, pkg, retries, resp.StatusCode, duration, "available")
+	return true
+}
+
+// GetPackageCount returns the number of packages
+func (c *CargoLookup) GetPackageCount() int {
+	return len(c.Packages)
+}
+
+// GetLanguage returns the language name
+func (c *CargoLookup) GetLanguage() string {
+	return "cargo"
+}
+
+// SetContext sets the context for the resolver
+func (c *CargoLookup) SetContext(ctx context.Context) {
+	c.ctx = ctx
+}
+
+// SetTimeout sets the timeout for requests
+func (c *CargoLookup) SetTimeout(timeout time.Duration) {
+	c.timeout = timeout
+}
+
+// SetRateLimit sets the rate limit for requests
+func (c *CargoLookup) SetRateLimit(rate int) {
+	c.rateLimit = rate
+}
+
+// GetPackageDetails returns detailed information about packages
+func (c *CargoLookup) GetPackageDetails() []types.PackageDetail {
+	if len(c.packageDetails) == 0 {
+		c.buildPackageDetails()
+	}
+	return c.packageDetails
+}
+
+// buildPackageDetails builds detailed package information.
+func (c *CargoLookup) buildPackageDetails() {
+	c.packageDetails = []types.PackageDetail{}
+
+	for _, pkg := range c.Packages {
+		detail := types.PackageDetail{
+			Name:    pkg.Name,
+			Version: pkg.Version,
+			Type:    "dependency",
+			Metadata: map[string]interface{}{
+				"original_name": pkg.Name,
+			},
+		}
+
+		if c.isConfused(pkg) {
+			detail.Vulnerable = true
+			detail.Reason = "Crate not available in public crates.io registry"
+		}
+
+		for _, reg := range c.registrySet.Public("cargo", defaultCratesRegistry) {
+			if matched, ok, versions := CheckVersionAgainstFetch(pkg.Version, func() ([]string, error) {
+				return fetchCargoVersions(reg, pkg.Name)
+			}); ok {
+				detail.Metadata["version_matched"] = matched
+				detail.Metadata["available_versions"] = versions
+			}
+			break
+		}
+
+		c.packageDetails = append(c.packageDetails, detail)
+	}
+}
+
+// cratesIOResponse mirrors the bits of crates.io's crate metadata endpoint
+// needed to list a crate's published versions.
+type cratesIOResponse struct {
+	Versions []struct {
+		Num string `json:"num"`
+	} `json:"versions"`
+}
+
+// fetchCargoVersions fetches the published versions for name from reg's
+// crate metadata endpoint.
+func fetchCargoVersions(reg registries.Registry, name string) ([]string, error) {
+	url := reg.URL + "/" + name
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+	reg.ApplyAuth(req)
+	resp, err := reg.Client(0).Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("crate metadata not found at %s (status %s)", url, resp.Status)
+	}
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	var data cratesIOResponse
+	if err := json.Unmarshal(body, &data); err != nil {
+		return nil, err
+	}
+	versions := make([]string, 0, len(data.Versions))
+	for _, v := range data.Versions {
+		versions = append(versions, v.Num)
+	}
+	return versions, nil
+}
@@ -0,0 +1,29 @@
+package resolvers
+
+import "testing"
+
+func TestCargoLookup_ReadPackagesFromFile(t *testing.T) {
+	lookup := NewCargoLookup(false).(*CargoLookup)
+
+	if err := lookup.ReadPackagesFromFile("testdata/Cargo.toml.fixture"); err != nil {
+		t.Fatalf("ReadPackagesFromFile returned error: %v", err)
+	}
+
+	want := []CargoPackage{
+		{Name: "serde", Version: "1.0"},
+		{Name: "tokio", Version: "1"},
+		{Name: "internal-only-crate", Version: "0.0.1"},
+	}
+	if len(lookup.Packages) != len(want) {
+		t.Fatalf("got %d packages, want %d: %+v", len(lookup.Packages), len(want), lookup.Packages)
+	}
+	for i, pkg := range want {
+		if lookup.Packages[i] != pkg {
+			t.Errorf("package %d = %+v, want %+v", i, lookup.Packages[i], pkg)
+		}
+	}
+
+	if got := lookup.GetLanguage(); got != "cargo" {
+		t.Errorf("GetLanguage() = %q, want %q", got, "cargo")
+	}
+}
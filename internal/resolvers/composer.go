@@ -9,28 +9,85 @@ import (
 	"strings"
 	"time"
 
-	"github.com/h0tak88r/confused2/internal/types"
+	"github.com/h0tak88r/confused/internal/cache"
+	"github.com/h0tak88r/confused/internal/types"
+	"github.com/h0tak88r/confused/pkg/logger"
+	"github.com/h0tak88r/confused/pkg/registries"
+	"golang.org/x/time/rate"
 )
 
+// defaultPackagistRegistry is packagist.org, used when no --registry/
+// --internal-registry flag overrides the "composer" ecosystem.
+const defaultPackagistRegistry = "https://packagist.org/packages"
+
 // ComposerJSON represents the dependencies of a composer package
 type ComposerJSON struct {
 	Require    map[string]string `json:"require,omitempty"`
 	RequireDev map[string]string `json:"require-dev,omitempty"`
 }
 
+// ComposerLockJSON represents the subset of a composer.lock file needed to
+// recover each resolved package's name, version, and pinned dist shasum.
+type ComposerLockJSON struct {
+	Packages    []ComposerLockPackage `json:"packages,omitempty"`
+	PackagesDev []ComposerLockPackage `json:"packages-dev,omitempty"`
+}
+
+// ComposerLockPackage is a single resolved entry in composer.lock.
+type ComposerLockPackage struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+	Dist    struct {
+		Shasum string `json:"shasum"`
+	} `json:"dist"`
+}
+
 // ComposerLookup represents a collection of composer packages to be tested for dependency confusion.
 type ComposerLookup struct {
-	Packages       []ComposerPackage
-	Verbose        bool
-	ctx            context.Context
-	timeout        time.Duration
-	rateLimit      int
-	packageDetails []types.PackageDetail
+	Packages          []ComposerPackage
+	Verbose           bool
+	ctx               context.Context
+	timeout           time.Duration
+	rateLimit         int
+	limiter           *rate.Limiter
+	registrySet       *registries.Set
+	log               *logger.Logger
+	workers           int
+	packageDetails    []types.PackageDetail
+	notAvailableCache []string
+	resolved          bool
+	cache             cache.Store
+	cacheTTL          time.Duration
+	httpClient        *http.Client
+	metrics           *Metrics
+}
+
+// SetCache wires store/ttl in so isAvailableInPublic checks are served
+// from cache before falling through to a live registry probe.
+func (c *ComposerLookup) SetCache(store cache.Store, ttl time.Duration) {
+	c.cache = store
+	c.cacheTTL = ttl
+}
+
+// SetHTTPClient points this resolver at a shared, instrumented *http.Client
+// (see ResolveAll) instead of building one per registry via Registry.Client.
+func (c *ComposerLookup) SetHTTPClient(client *http.Client) {
+	c.httpClient = client
+}
+
+// SetMetrics points this resolver at a shared Metrics instance so its
+// registry checks are counted alongside every other resolver ResolveAll runs.
+func (c *ComposerLookup) SetMetrics(m *Metrics) {
+	c.metrics = m
 }
 
 type ComposerPackage struct {
 	Name    string
 	Version string
+	// PinnedShasum is the dist archive shasum pinned for this package in
+	// composer.lock, if the input file was a lock file rather than
+	// composer.json. Empty when no pin is available.
+	PinnedShasum string
 }
 
 // NewComposerLookup constructs a `ComposerLookup` struct and returns it.
@@ -41,11 +98,17 @@ func NewComposerLookup(verbose bool) types.PackageResolver {
 		ctx:            context.Background(),
 		timeout:        30 * time.Second,
 		rateLimit:      100,
+		workers:        defaultWorkers,
 		packageDetails: []types.PackageDetail{},
 	}
 }
 
-// ReadPackagesFromFile reads package information from a composer `composer.json` file
+// ReadPackagesFromFile reads package information from a composer
+// `composer.json` or `composer.lock` file. The two are distinguished by
+// content rather than filename, since callers (e.g. the web scanner) may
+// hand this a generically-named temp file: a composer.lock has a top-level
+// "packages"/"packages-dev" array, while composer.json has "require"/
+// "require-dev" maps.
 //
 // Returns any errors encountered
 func (c *ComposerLookup) ReadPackagesFromFile(filename string) error {
@@ -53,16 +116,28 @@ func (c *ComposerLookup) ReadPackagesFromFile(filename string) error {
 	if err != nil {
 		return err
 	}
+
+	lock := ComposerLockJSON{}
+	if err := json.Unmarshal(rawfile, &lock); err == nil && (len(lock.Packages) > 0 || len(lock.PackagesDev) > 0) {
+		for _, pkg := range lock.Packages {
+			c.Packages = append(c.Packages, ComposerPackage{pkg.Name, pkg.Version, pkg.Dist.Shasum})
+		}
+		for _, pkg := range lock.PackagesDev {
+			c.Packages = append(c.Packages, ComposerPackage{pkg.Name, pkg.Version, pkg.Dist.Shasum})
+		}
+		return nil
+	}
+
 	data := ComposerJSON{}
-	err = json.Unmarshal([]byte(rawfile), &data)
+	err = json.Unmarshal(rawfile, &data)
 	if err != nil {
 		fmt.Printf(" [W] Non-fatal issue encountered while reading %s : %s\n", filename, err)
 	}
 	for pkgname, pkgversion := range data.Require {
-		c.Packages = append(c.Packages, ComposerPackage{pkgname, pkgversion})
+		c.Packages = append(c.Packages, ComposerPackage{Name: pkgname, Version: pkgversion})
 	}
 	for pkgname, pkgversion := range data.RequireDev {
-		c.Packages = append(c.Packages, ComposerPackage{pkgname, pkgversion})
+		c.Packages = append(c.Packages, ComposerPackage{Name: pkgname, Version: pkgversion})
 	}
 	return nil
 }
@@ -71,46 +146,170 @@ func (c *ComposerLookup) ReadPackagesFromFile(filename string) error {
 //
 // Returns a slice of strings with any composer packages not in the public composer package repository
 func (c *ComposerLookup) PackagesNotInPublic() []string {
-	notavail := []string{}
-	for _, pkg := range c.Packages {
+	if c.resolved {
+		return c.notAvailableCache
+	}
+	notavail, _ := c.resolve(context.Background())
+	return notavail
+}
+
+// ResolveWithContext behaves like PackagesNotInPublic but aborts as soon as
+// ctx is cancelled and honors the rate limiter set via SetLimiter, so a
+// SIGINT during an org scan stops in-flight registry probes instead of
+// running to completion.
+func (c *ComposerLookup) ResolveWithContext(ctx context.Context) error {
+	c.ctx = ctx
+	notavail, err := c.resolve(ctx)
+	if err != nil {
+		return err
+	}
+	c.notAvailableCache = notavail
+	c.resolved = true
+	return nil
+}
+
+// resolve dispatches an isConfused check per package across c.workers
+// goroutines, rate-limited by c.limiter, returning the confused packages in
+// the same order as c.Packages. Packages whose version is a local/URL/git
+// reference are never flagged, matching the serial resolver's behavior.
+func (c *ComposerLookup) resolve(ctx context.Context) ([]string, error) {
+	indices, err := resolveConcurrently(ctx, len(c.Packages), c.workers, func(ctx context.Context, i int) (bool, error) {
+		pkg := c.Packages[i]
 		if c.localReference(pkg.Version) || c.urlReference(pkg.Version) || c.gitReference(pkg.Version) {
-			continue
+			return false, nil
 		}
-		if !c.isAvailableInPublic(pkg.Name, 0) {
-			notavail = append(notavail, pkg.Name)
+		if err := waitForToken(ctx, c.limiter); err != nil {
+			return false, err
 		}
+		return c.isConfused(pkg), nil
+	})
+	if err != nil {
+		return nil, err
 	}
-	return notavail
+	notavail := make([]string, 0, len(indices))
+	for _, i := range indices {
+		notavail = append(notavail, c.Packages[i].Name)
+	}
+	return notavail, nil
 }
 
-// isAvailableInPublic determines if a composer package exists in the public composer package repository.
-//
-// Returns true if the package exists in the public composer package repository.
-func (c *ComposerLookup) isAvailableInPublic(pkgname string, retry int) bool {
-	if retry > 3 {
-		fmt.Printf(" [W] Maximum number of retries exhausted for package: %s\n", pkgname)
+// SetLimiter points this resolver at a shared rate limiter.
+func (c *ComposerLookup) SetLimiter(limiter *rate.Limiter) {
+	c.limiter = limiter
+}
+
+// SetWorkers points this resolver at the configured --workers concurrency
+// for its registry probes.
+func (c *ComposerLookup) SetWorkers(workers int) {
+	if workers > 0 {
+		c.workers = workers
+	}
+}
+
+// SetRegistries points this resolver at the configured public/internal
+// Packagist mirrors, so a package only counts as "public" if it's found in
+// one of the configured public registries (defaulting to packagist.org), and
+// only counts as confused if it's also present in a configured internal one.
+func (c *ComposerLookup) SetRegistries(regs *registries.Set) {
+	c.registrySet = regs
+}
+
+// SetLogger points this resolver at a structured logger, so each registry
+// HTTP check emits one event record instead of an ad-hoc fmt.Printf line.
+func (c *ComposerLookup) SetLogger(log *logger.Logger) {
+	c.log = log
+}
+
+// logCheck emits a structured "registry_check" event for a single HTTP
+// check. If no logger was wired via SetLogger, it falls back to printing a
+// human-readable line so the resolver still works when used standalone.
+func (c *ComposerLookup) logCheck(reg registries.Registry, pkg ComposerPackage, retry int, statusCode int, duration time.Duration, result string) {
+	if c.log == nil {
+		fmt.Printf(" [%s] %s (registry=%s status=%d retry=%d)\n", result, pkg.Name, reg.URL, statusCode, retry)
+		return
+	}
+	c.log.WithFields(map[string]interface{}{
+		"event":       "registry_check",
+		"registry":    reg.URL,
+		"package":     pkg.Name,
+		"version":     pkg.Version,
+		"status_code": statusCode,
+		"retry":       retry,
+		"duration_ms": duration.Milliseconds(),
+		"result":      result,
+	}).Debug("composer registry check")
+}
+
+// isConfused reports whether pkg is absent from every configured public
+// Packagist registry and, when an internal allowlist was configured, present
+// (or expected, if no internal allowlist was configured) in one of those.
+func (c *ComposerLookup) isConfused(pkg ComposerPackage) bool {
+	if c.isAvailableInPublic(pkg) {
 		return false
 	}
-	if c.Verbose {
-		fmt.Print("Checking: https://packagist.org/packages/" + pkgname + ".json : ")
+	if len(c.registrySet.Internal("composer")) == 0 {
+		return true
+	}
+	return c.isAvailableInInternal(pkg)
+}
+
+// isAvailableInPublic determines if a composer package exists in any of the
+// configured public Packagist registries.
+//
+// Returns true if the package exists in a public Packagist registry.
+func (c *ComposerLookup) isAvailableInPublic(pkg ComposerPackage) bool {
+	if available, ok := cachedAvailability(c.cache, "composer", pkg.Name, pkg.Version); ok {
+		c.metrics.RecordCacheHit()
+		return available
+	}
+	available := false
+	for _, reg := range c.registrySet.Public("composer", defaultPackagistRegistry) {
+		if c.checkRegistry(reg, pkg) {
+			available = true
+			break
+		}
+	}
+	setCachedAvailability(c.cache, c.cacheTTL, "composer", pkg.Name, pkg.Version, available)
+	return available
+}
+
+// isAvailableInInternal determines if a composer package exists in any of
+// the registries configured as "internal" via --internal-registry.
+func (c *ComposerLookup) isAvailableInInternal(pkg ComposerPackage) bool {
+	for _, reg := range c.registrySet.Internal("composer") {
+		if c.checkRegistry(reg, pkg) {
+			return true
+		}
 	}
-	resp, err := http.Get("https://packagist.org/packages/" + pkgname + ".json")
+	return false
+}
+
+// checkRegistry determines if a composer package exists in a single
+// registry. 429/5xx are retried with backoff centrally by doRegistryGET.
+func (c *ComposerLookup) checkRegistry(reg registries.Registry, pkg ComposerPackage) bool {
+	pkgname := pkg.Name
+	url := reg.URL + "/" + pkgname + ".json"
+	resp, duration, retries, err := doRegistryGET(reg, url, c.timeout, c.httpClient, c.metrics)
 	if err != nil {
-		fmt.Printf(" [W] Error when trying to request https://packagist.org/packages/"+pkgname+".json : %s\n", err)
+		c.logCheck(reg, pkg, retries, 0, duration, fmt.Sprintf("request_error: %s", err))
 		return false
 	}
 	defer resp.Body.Close()
-	if c.Verbose {
-		fmt.Printf("%s\n", resp.Status)
+	if resp.StatusCode == http.StatusTooManyRequests {
+		c.logCheck(reg, pkg, retries, resp.StatusCode, duration, "retries_exhausted")
+		return false
 	}
 	if resp.StatusCode == http.StatusOK {
+		if matched, ok, _ := CheckVersionAgainstFetch(pkg.Version, func() ([]string, error) {
+			return fetchComposerVersions(reg, pkgname)
+		}); ok && !matched {
+			c.logCheck(reg, pkg, retries, resp.StatusCode, duration, "version_not_published")
+			return false
+		}
+		c.logCheck(reg, pkg, retries, resp.StatusCode, duration, "available")
 		return true
-	} else if resp.StatusCode == 429 {
-		fmt.Printf(" [!] Server responded with 429 (Too many requests), throttling and retrying...\n")
-		time.Sleep(10 * time.Second)
-		retry = retry + 1
-		return c.isAvailableInPublic(pkgname, retry)
 	}
+	c.logCheck(reg, pkg, retries, resp.StatusCode, duration, "not_available")
 	return false
 }
 
@@ -173,7 +372,7 @@ func (c *ComposerLookup) GetPackageDetails() []types.PackageDetail {
 // buildPackageDetails builds detailed package information
 func (c *ComposerLookup) buildPackageDetails() {
 	c.packageDetails = []types.PackageDetail{}
-	
+
 	for _, pkg := range c.Packages {
 		detail := types.PackageDetail{
 			Name:    pkg.Name,
@@ -183,13 +382,51 @@ func (c *ComposerLookup) buildPackageDetails() {
 				"original_version": pkg.Version,
 			},
 		}
-		
+
 		// Check if package is vulnerable
-		if !c.isAvailableInPublic(pkg.Name, 0) {
+		if c.isConfused(pkg) {
 			detail.Vulnerable = true
 			detail.Reason = "Package not available in public Packagist registry"
 		}
-		
+
+		for _, reg := range c.registrySet.Public("composer", defaultPackagistRegistry) {
+			if matched, ok, versions := CheckVersionAgainstFetch(pkg.Version, func() ([]string, error) {
+				return fetchComposerVersions(reg, pkg.Name)
+			}); ok {
+				detail.Metadata["version_matched"] = matched
+				detail.Metadata["available_versions"] = versions
+				break
+			}
+		}
+
+		// If pkg came from a composer.lock pin, compare its dist shasum
+		// against what Packagist currently publishes for that exact version.
+		// A mismatch means the artifact behind that version/registry was
+		// swapped after the lock was committed - a supply-chain signal the
+		// namespace-presence check above can't see.
+		if pkg.PinnedShasum != "" && !detail.Vulnerable {
+			c.checkIntegrity(pkg, &detail)
+		}
+
 		c.packageDetails = append(c.packageDetails, detail)
 	}
 }
+
+// checkIntegrity compares pkg's composer.lock-pinned dist shasum against the
+// shasum Packagist currently publishes for that version, flagging a mismatch
+// as integrity drift.
+func (c *ComposerLookup) checkIntegrity(pkg ComposerPackage, detail *types.PackageDetail) {
+	for _, reg := range c.registrySet.Public("composer", defaultPackagistRegistry) {
+		published, err := fetchComposerDistShasum(reg, pkg.Name, pkg.Version)
+		if err != nil || published == "" {
+			continue
+		}
+		detail.Metadata["pinned_shasum"] = pkg.PinnedShasum
+		detail.Metadata["published_shasum"] = published
+		if published != pkg.PinnedShasum {
+			detail.Vulnerable = true
+			detail.Reason = "integrity drift"
+		}
+		return
+	}
+}
@@ -0,0 +1,85 @@
+package resolvers
+
+import (
+	"context"
+	"sync"
+)
+
+// WorkersAware is implemented by resolvers that can be pointed at the
+// configured --workers concurrency, so PackagesNotInPublic/ResolveWithContext
+// dispatch registry probes through a worker pool instead of one at a time.
+type WorkersAware interface {
+	SetWorkers(workers int)
+}
+
+// defaultWorkers is used by resolvers that were never wired with SetWorkers
+// (e.g. constructed directly, outside the factory).
+const defaultWorkers = 10
+
+// resolveConcurrently evaluates isConfused(i) for every i in [0,n) across up
+// to workers goroutines, then returns the indices for which it reported
+// true, sorted ascending so callers get the same deterministic order as a
+// serial loop would. It returns ctx.Err() (without waiting for in-flight
+// work to finish) as soon as ctx is cancelled.
+func resolveConcurrently(ctx context.Context, n int, workers int, isConfused func(ctx context.Context, i int) (bool, error)) ([]int, error) {
+	if workers <= 0 {
+		workers = 1
+	}
+	if workers > n {
+		workers = n
+	}
+	if n == 0 {
+		return nil, nil
+	}
+
+	jobs := make(chan int)
+	bad := make([]bool, n)
+	var wg sync.WaitGroup
+	errCh := make(chan error, 1)
+
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for idx := range jobs {
+				confused, err := isConfused(ctx, idx)
+				if err != nil {
+					select {
+					case errCh <- err:
+					default:
+					}
+					continue
+				}
+				bad[idx] = confused
+			}
+		}()
+	}
+
+dispatch:
+	for i := 0; i < n; i++ {
+		select {
+		case jobs <- i:
+		case <-ctx.Done():
+			break dispatch
+		}
+	}
+	close(jobs)
+	wg.Wait()
+
+	select {
+	case err := <-errCh:
+		return nil, err
+	default:
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	var idxs []int
+	for i, b := range bad {
+		if b {
+			idxs = append(idxs, i)
+		}
+	}
+	return idxs, nil
+}
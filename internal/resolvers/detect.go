@@ -0,0 +1,125 @@
+package resolvers
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/h0tak88r/confused/internal/types"
+)
+
+// languageManifestPatterns maps each ecosystem to the manifest filenames
+// that indicate its presence, mirroring pkg/github's findDependencyFiles
+// so a local monorepo scan recognizes the same fingerprints a GitHub scan
+// does.
+var languageManifestPatterns = map[string][]string{
+	"pip":      {"requirements.txt", "requirements-dev.txt", "setup.py", "pyproject.toml", "Pipfile"},
+	"npm":      {"package.json", "package-lock.json", "yarn.lock"},
+	"composer": {"composer.json", "composer.lock"},
+	"mvn":      {"pom.xml", "build.gradle"},
+	"rubygems": {"Gemfile", "Gemfile.lock", "gems.rb"},
+	"go":       {"go.mod", "go.sum"},
+	"cargo":    {"Cargo.toml", "Cargo.lock"},
+	"nuget":    {"packages.config"},
+}
+
+// skippedDetectDirs are directories whose contents belong to a detected
+// ecosystem's own dependency tree rather than the project itself, and
+// would otherwise wildly inflate that ecosystem's confidence.
+var skippedDetectDirs = map[string]bool{
+	".git":         true,
+	"node_modules": true,
+	"vendor":       true,
+}
+
+// DetectedLanguage is one package-manager ecosystem found while walking a
+// directory tree, along with the manifests that evidenced it. Confidence
+// is the number of matching manifest files found, so callers can rank a
+// monorepo's ecosystems by how much of the tree they occupy - the same
+// weight-by-evidence approach tools like GitHub Linguist/Scorecard use to
+// rank a repo's languages, rather than trusting a single file hit.
+type DetectedLanguage struct {
+	Language      string
+	ManifestPaths []string
+	Confidence    int
+}
+
+// DetectLanguages walks root looking for manifest fingerprints and returns
+// one DetectedLanguage per ecosystem found, ordered by descending
+// confidence (most manifests first).
+func DetectLanguages(root string) ([]DetectedLanguage, error) {
+	found := make(map[string][]string)
+
+	err := filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			if path != root && skippedDetectDirs[info.Name()] {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		name := filepath.Base(path)
+		for lang, patterns := range languageManifestPatterns {
+			for _, pattern := range patterns {
+				if name == pattern {
+					found[lang] = append(found[lang], path)
+					break
+				}
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to walk %s: %w", root, err)
+	}
+
+	languages := make([]DetectedLanguage, 0, len(found))
+	for lang, manifests := range found {
+		languages = append(languages, DetectedLanguage{
+			Language:      lang,
+			ManifestPaths: manifests,
+			Confidence:    len(manifests),
+		})
+	}
+	sort.Slice(languages, func(i, j int) bool {
+		if languages[i].Confidence != languages[j].Confidence {
+			return languages[i].Confidence > languages[j].Confidence
+		}
+		return languages[i].Language < languages[j].Language
+	})
+
+	return languages, nil
+}
+
+// DetectAndResolve scans root for manifest fingerprints and returns one
+// resolver per detected ecosystem, each already loaded (via
+// ReadPackagesFromFile) with every matching manifest found, so a caller
+// can run PackagesNotInPublic/ResolveWithContext against a monorepo
+// without invoking confused once per ecosystem. Ecosystems without a
+// registered constructor (see GetResolverForLanguage) are skipped rather
+// than failing the whole scan.
+func DetectAndResolve(path string) ([]types.PackageResolver, error) {
+	languages, err := DetectLanguages(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var resolvers []types.PackageResolver
+	for _, lang := range languages {
+		resolver, err := GetResolverForLanguage(lang.Language)
+		if err != nil {
+			continue
+		}
+		for _, manifest := range lang.ManifestPaths {
+			if err := resolver.ReadPackagesFromFile(manifest); err != nil {
+				return nil, fmt.Errorf("failed to read %s: %w", manifest, err)
+			}
+		}
+		resolvers = append(resolvers, resolver)
+	}
+
+	return resolvers, nil
+}
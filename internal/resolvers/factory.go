@@ -1,8 +1,15 @@
 package resolvers
 
 import (
+	"context"
 	"fmt"
-	"github.com/h0tak88r/confused2/internal/types"
+	"time"
+
+	"github.com/h0tak88r/confused/internal/cache"
+	"github.com/h0tak88r/confused/internal/types"
+	"github.com/h0tak88r/confused/pkg/logger"
+	"github.com/h0tak88r/confused/pkg/registries"
+	"golang.org/x/time/rate"
 )
 
 // GetResolverForLanguage returns a resolver for the given language
@@ -18,6 +25,12 @@ func GetResolverForLanguage(language string) (types.PackageResolver, error) {
 		return NewMVNLookup(false), nil
 	case "rubygems":
 		return NewRubyGemsLookup(false), nil
+	case "go":
+		return NewGoModLookup(false), nil
+	case "cargo":
+		return NewCargoLookup(false), nil
+	case "nuget":
+		return NewNuGetLookup(false), nil
 	default:
 		return nil, fmt.Errorf("unsupported language: %s", language)
 	}
@@ -36,7 +49,60 @@ func GetResolverForLanguageWithVerbose(language string, verbose bool) (types.Pac
 		return NewMVNLookup(verbose), nil
 	case "rubygems":
 		return NewRubyGemsLookup(verbose), nil
+	case "go":
+		return NewGoModLookup(verbose), nil
+	case "cargo":
+		return NewCargoLookup(verbose), nil
+	case "nuget":
+		return NewNuGetLookup(verbose), nil
 	default:
 		return nil, fmt.Errorf("unsupported language: %s", language)
 	}
 }
+
+// GetResolverForLanguageWithContext returns a resolver for the given language,
+// pre-wired with ctx, limiter, regs, log, and workers so its
+// ResolveWithContext calls are cancellable, rate-limited, aware of any
+// configured registry mirrors, emit structured log events instead of ad-hoc
+// fmt.Printf lines, and dispatch registry probes across a worker pool sized
+// to --workers instead of one package at a time. Resolvers that don't
+// implement ContextAware/registries.Aware/LoggerAware/WorkersAware (i.e.
+// don't support rate limiting, mirrors, structured logging, or concurrency
+// yet) are returned as-is.
+func GetResolverForLanguageWithContext(language string, verbose bool, ctx context.Context, limiter *rate.Limiter, regs *registries.Set, log *logger.Logger, workers int) (types.PackageResolver, error) {
+	resolver, err := GetResolverForLanguageWithVerbose(language, verbose)
+	if err != nil {
+		return nil, err
+	}
+	if enhanced, ok := resolver.(types.EnhancedPackageResolver); ok {
+		enhanced.SetContext(ctx)
+	}
+	if aware, ok := resolver.(ContextAware); ok {
+		aware.SetLimiter(limiter)
+	}
+	if regAware, ok := resolver.(registries.Aware); ok {
+		regAware.SetRegistries(regs)
+	}
+	if logAware, ok := resolver.(LoggerAware); ok {
+		logAware.SetLogger(log)
+	}
+	if workersAware, ok := resolver.(WorkersAware); ok {
+		workersAware.SetWorkers(workers)
+	}
+	return resolver, nil
+}
+
+// GetResolverForLanguageWithCache behaves like GetResolverForLanguageWithContext
+// but additionally wires cacheStore/cacheTTL into resolvers that implement
+// CacheAware, so registry availability checks are memoized across the scan.
+// A nil cacheStore leaves caching disabled.
+func GetResolverForLanguageWithCache(language string, verbose bool, ctx context.Context, limiter *rate.Limiter, regs *registries.Set, log *logger.Logger, workers int, cacheStore cache.Store, cacheTTL time.Duration) (types.PackageResolver, error) {
+	resolver, err := GetResolverForLanguageWithContext(language, verbose, ctx, limiter, regs, log, workers)
+	if err != nil {
+		return nil, err
+	}
+	if cacheAware, ok := resolver.(CacheAware); ok {
+		cacheAware.SetCache(cacheStore, cacheTTL)
+	}
+	return resolver, nil
+}
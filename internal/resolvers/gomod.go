@@ -0,0 +1,406 @@
+package resolvers
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/h0tak88r/confused/internal/cache"
+	"github.com/h0tak88r/confused/internal/types"
+	"github.com/h0tak88r/confused/pkg/logger"
+	"github.com/h0tak88r/confused/pkg/registries"
+	"golang.org/x/time/rate"
+)
+
+// defaultGoProxyRegistry is proxy.golang.org, used when no --registry/
+// --internal-registry flag overrides the "go" ecosystem.
+const defaultGoProxyRegistry = "https://proxy.golang.org"
+
+// goModRequirePattern matches a single `require` line, inside or outside a
+// `require (...)` block, e.g. "github.com/foo/bar v1.2.3 // indirect".
+var goModRequirePattern = regexp.MustCompile(`^([^\s]+)\s+(v[0-9][^\s]*)`)
+
+// GoModPackage is a single module requirement parsed from a go.mod file.
+type GoModPackage struct {
+	Module  string
+	Version string
+}
+
+// GoModLookup represents a collection of Go modules to be tested for dependency confusion.
+type GoModLookup struct {
+	Packages          []GoModPackage
+	Verbose           bool
+	ctx               context.Context
+	timeout           time.Duration
+	rateLimit         int
+	limiter           *rate.Limiter
+	registrySet       *registries.Set
+	log               *logger.Logger
+	workers           int
+	packageDetails    []types.PackageDetail
+	notAvailableCache []string
+	resolved          bool
+	cache             cache.Store
+	cacheTTL          time.Duration
+	httpClient        *http.Client
+	metrics           *Metrics
+}
+
+// NewGoModLookup constructs a `GoModLookup` struct and returns it.
+func NewGoModLookup(verbose bool) types.PackageResolver {
+	return &GoModLookup{
+		Packages:       []GoModPackage{},
+		Verbose:        verbose,
+		ctx:            context.Background(),
+		timeout:        30 * time.Second,
+		rateLimit:      100,
+		workers:        defaultWorkers,
+		packageDetails: []types.PackageDetail{},
+	}
+}
+
+// SetCache wires store/ttl in so isAvailableInPublic checks are served
+// from cache before falling through to a live registry probe.
+func (g *GoModLookup) SetCache(store cache.Store, ttl time.Duration) {
+	g.cache = store
+	g.cacheTTL = ttl
+}
+
+// SetHTTPClient points this resolver at a shared, instrumented *http.Client
+// (see ResolveAll) instead of building one per registry via Registry.Client.
+func (g *GoModLookup) SetHTTPClient(client *http.Client) {
+	g.httpClient = client
+}
+
+// SetMetrics points this resolver at a shared Metrics instance so its
+// registry checks are counted alongside every other resolver ResolveAll runs.
+func (g *GoModLookup) SetMetrics(m *Metrics) {
+	g.metrics = m
+}
+
+// ReadPackagesFromFile reads package information from a go.mod file.
+//
+// Returns any errors encountered
+func (g *GoModLookup) ReadPackagesFromFile(filename string) error {
+	rawfile, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return err
+	}
+
+	if g.Verbose {
+		fmt.Print("Checking: filename: " + filename + "\n")
+	}
+
+	inRequireBlock := false
+	for _, l := range strings.Split(string(rawfile), "\n") {
+		l = strings.TrimSpace(l)
+		if l == "" || strings.HasPrefix(l, "//") {
+			continue
+		}
+
+		if inRequireBlock {
+			if l == ")" {
+				inRequireBlock = false
+				continue
+			}
+			if m := goModRequirePattern.FindStringSubmatch(l); m != nil {
+				g.Packages = append(g.Packages, GoModPackage{Module: m[1], Version: m[2]})
+			}
+			continue
+		}
+
+		if l == "require (" {
+			inRequireBlock = true
+			continue
+		}
+		if strings.HasPrefix(l, "require ") {
+			if m := goModRequirePattern.FindStringSubmatch(strings.TrimPrefix(l, "require ")); m != nil {
+				g.Packages = append(g.Packages, GoModPackage{Module: m[1], Version: m[2]})
+			}
+		}
+	}
+
+	return nil
+}
+
+// PackagesNotInPublic determines if a Go module does not exist in the
+// configured public Go module proxy.
+//
+// Returns a slice of strings with any Go modules not in the public proxy
+func (g *GoModLookup) PackagesNotInPublic() []string {
+	if g.resolved {
+		return g.notAvailableCache
+	}
+	notavail, _ := g.resolve(context.Background())
+	return notavail
+}
+
+// ResolveWithContext behaves like PackagesNotInPublic but aborts as soon as
+// ctx is cancelled and honors the rate limiter set via SetLimiter, so a
+// SIGINT during an org scan stops in-flight registry probes instead of
+// running to completion.
+func (g *GoModLookup) ResolveWithContext(ctx context.Context) error {
+	g.ctx = ctx
+	notavail, err := g.resolve(ctx)
+	if err != nil {
+		return err
+	}
+	g.notAvailableCache = notavail
+	g.resolved = true
+	return nil
+}
+
+// resolve dispatches an isConfused check per module across g.workers
+// goroutines, rate-limited by g.limiter, returning the confused modules in
+// the same order as g.Packages.
+func (g *GoModLookup) resolve(ctx context.Context) ([]string, error) {
+	indices, err := resolveConcurrently(ctx, len(g.Packages), g.workers, func(ctx context.Context, i int) (bool, error) {
+		if err := waitForToken(ctx, g.limiter); err != nil {
+			return false, err
+		}
+		return g.isConfused(g.Packages[i]), nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	notavail := make([]string, 0, len(indices))
+	for _, i := range indices {
+		notavail = append(notavail, g.Packages[i].Module)
+	}
+	return notavail, nil
+}
+
+// SetLimiter points this resolver at a shared rate limiter.
+func (g *GoModLookup) SetLimiter(limiter *rate.Limiter) {
+	g.limiter = limiter
+}
+
+// SetWorkers points this resolver at the configured --workers concurrency
+// for its registry probes.
+func (g *GoModLookup) SetWorkers(workers int) {
+	if workers > 0 {
+		g.workers = workers
+	}
+}
+
+// SetRegistries points this resolver at the configured public/internal Go
+// module proxies, so a module only counts as "public" if it's found in one
+// of the configured public proxies (defaulting to proxy.golang.org), and
+// only counts as confused if it's also present in a configured internal one.
+func (g *GoModLookup) SetRegistries(regs *registries.Set) {
+	g.registrySet = regs
+}
+
+// SetLogger points this resolver at a structured logger, so each registry
+// HTTP check emits one event record instead of an ad-hoc fmt.Printf line.
+func (g *GoModLookup) SetLogger(log *logger.Logger) {
+	g.log = log
+}
+
+// logCheck emits a structured "registry_check" event for a single HTTP
+// check. If no logger was wired via SetLogger, it falls back to printing a
+// human-readable line so the resolver still works when used standalone.
+func (g *GoModLookup) logCheck(reg registries.Registry, pkg GoModPackage, retry int, statusCode int, duration time.Duration, result string) {
+	if g.log == nil {
+		fmt.Printf(" [%s] %s (registry=%s status=%d retry=%d)\n", result, pkg.Module, reg.URL, statusCode, retry)
+		return
+	}
+	g.log.WithFields(map[string]interface{}{
+		"event":       "registry_check",
+		"registry":    reg.URL,
+		"package":     pkg.Module,
+		"version":     pkg.Version,
+		"status_code": statusCode,
+		"retry":       retry,
+		"duration_ms": duration.Milliseconds(),
+		"result":      result,
+	}).Debug("go registry check")
+}
+
+// isConfused reports whether pkg is absent from every configured public Go
+// proxy and, when an internal allowlist was configured, present (or
+// expected, if no internal allowlist was configured) in one of those.
+func (g *GoModLookup) isConfused(pkg GoModPackage) bool {
+	if g.isAvailableInPublic(pkg) {
+		return false
+	}
+	if len(g.registrySet.Internal("go")) == 0 {
+		return true
+	}
+	return g.isAvailableInInternal(pkg)
+}
+
+// isAvailableInPublic determines if a Go module exists in any of the
+// configured public module proxies (proxy.golang.org by default).
+//
+// Returns true if the module exists in a public proxy.
+func (g *GoModLookup) isAvailableInPublic(pkg GoModPackage) bool {
+	if available, ok := cachedAvailability(g.cache, "go", pkg.Module, pkg.Version); ok {
+		g.metrics.RecordCacheHit()
+		return available
+	}
+	available := false
+	for _, reg := range g.registrySet.Public("go", defaultGoProxyRegistry) {
+		if g.checkRegistry(reg, pkg) {
+			available = true
+			break
+		}
+	}
+	setCachedAvailability(g.cache, g.cacheTTL, "go", pkg.Module, pkg.Version, available)
+	return available
+}
+
+// isAvailableInInternal determines if a Go module exists in any of the
+// registries configured as "internal" via --internal-registry.
+func (g *GoModLookup) isAvailableInInternal(pkg GoModPackage) bool {
+	for _, reg := range g.registrySet.Internal("go") {
+		if g.checkRegistry(reg, pkg) {
+			return true
+		}
+	}
+	return false
+}
+
+// checkRegistry determines if a Go module exists in a single proxy, via its
+// @v/list endpoint (https://proxy.golang.org/<module>/@v/list), which 404s
+// for a module path the proxy has never resolved. 429/5xx are retried with
+// backoff centrally by doRegistryGET.
+func (g *GoModLookup) checkRegistry(reg registries.Registry, pkg GoModPackage) bool {
+	url := reg.URL + "/" + escapeGoModulePath(pkg.Module) + "/@v/list"
+	resp, duration, retries, err := doRegistryGET(reg, url, g.timeout, g.httpClient, g.metrics)
+	if err != nil {
+		g.logCheck(reg, pkg, retries, 0, duration, fmt.Sprintf("request_error: %s", err))
+		return false
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		g.logCheck(reg, pkg, retries, resp.StatusCode, duration, "not_available")
+		return false
+	}
+	if matched, ok, _ := CheckVersionAgainstFetch(pkg.Version, func() ([]string, error) {
+		return fetchGoModVersions(reg, pkg.Module)
+	}); ok && !matched {
+		g.logCheck(reg, pkg, retries, resp.StatusCode, duration, "version_not_published")
+		return false
+	}
+	g.logCheck(reg, pkg, retries, resp.StatusCode, duration, "available")
+	return true
+}
+
+// GetPackageCount returns the number of packages
+func (g *GoModLookup) GetPackageCount() int {
+	return len(g.Packages)
+}
+
+// GetLanguage returns the language name
+func (g *GoModLookup) GetLanguage() string {
+	return "go"
+}
+
+// SetContext sets the context for the resolver
+func (g *GoModLookup) SetContext(ctx context.Context) {
+	g.ctx = ctx
+}
+
+// SetTimeout sets the timeout for requests
+func (g *GoModLookup) SetTimeout(timeout time.Duration) {
+	g.timeout = timeout
+}
+
+// SetRateLimit sets the rate limit for requests
+func (g *GoModLookup) SetRateLimit(rate int) {
+	g.rateLimit = rate
+}
+
+// GetPackageDetails returns detailed information about packages
+func (g *GoModLookup) GetPackageDetails() []types.PackageDetail {
+	if len(g.packageDetails) == 0 {
+		g.buildPackageDetails()
+	}
+	return g.packageDetails
+}
+
+// buildPackageDetails builds detailed package information.
+func (g *GoModLookup) buildPackageDetails() {
+	g.packageDetails = []types.PackageDetail{}
+
+	for _, pkg := range g.Packages {
+		detail := types.PackageDetail{
+			Name:    pkg.Module,
+			Version: pkg.Version,
+			Type:    "dependency",
+			Metadata: map[string]interface{}{
+				"module":  pkg.Module,
+				"version": pkg.Version,
+			},
+		}
+
+		if g.isConfused(pkg) {
+			detail.Vulnerable = true
+			detail.Reason = "Module not available in public Go proxy"
+		}
+
+		for _, reg := range g.registrySet.Public("go", defaultGoProxyRegistry) {
+			if matched, ok, versions := CheckVersionAgainstFetch(pkg.Version, func() ([]string, error) {
+				return fetchGoModVersions(reg, pkg.Module)
+			}); ok {
+				detail.Metadata["version_matched"] = matched
+				detail.Metadata["available_versions"] = versions
+			}
+			break
+		}
+
+		g.packageDetails = append(g.packageDetails, detail)
+	}
+}
+
+// escapeGoModulePath applies Go's module path escaping (an uppercase
+// letter becomes "!" followed by its lowercase form), since case-sensitive
+// module paths are stored case-folded on disk/proxy caches.
+func escapeGoModulePath(path string) string {
+	var b strings.Builder
+	for _, r := range path {
+		if r >= 'A' && r <= 'Z' {
+			b.WriteByte('!')
+			b.WriteRune(r + ('a' - 'A'))
+		} else {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// fetchGoModVersions fetches the published versions for module from reg's
+// @v/list endpoint, which returns one version per line.
+func fetchGoModVersions(reg registries.Registry, module string) ([]string, error) {
+	url := reg.URL + "/" + escapeGoModulePath(module) + "/@v/list"
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+	reg.ApplyAuth(req)
+	resp, err := reg.Client(0).Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("@v/list not found at %s (status %s)", url, resp.Status)
+	}
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	var versions []string
+	for _, line := range strings.Split(string(body), "\n") {
+		line = strings.TrimSpace(line)
+		if line != "" {
+			versions = append(versions, line)
+		}
+	}
+	return versions, nil
+}
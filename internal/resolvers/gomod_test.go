@@ -0,0 +1,32 @@
+package resolvers
+
+import "testing"
+
+func TestGoModLookup_ReadPackagesFromFile(t *testing.T) {
+	lookup := NewGoModLookup(false).(*GoModLookup)
+
+	if err := lookup.ReadPackagesFromFile("testdata/go.mod.fixture"); err != nil {
+		t.Fatalf("ReadPackagesFromFile returned error: %v", err)
+	}
+
+	want := []GoModPackage{
+		{Module: "github.com/h0tak88r/confused", Version: "v1.2.3"},
+		{Module: "golang.org/x/time", Version: "v0.15.0"},
+		{Module: "internal-only.example.com/widgets", Version: "v0.0.1"},
+	}
+	if len(lookup.Packages) != len(want) {
+		t.Fatalf("got %d packages, want %d: %+v", len(lookup.Packages), len(want), lookup.Packages)
+	}
+	for i, pkg := range want {
+		if lookup.Packages[i] != pkg {
+			t.Errorf("package %d = %+v, want %+v", i, lookup.Packages[i], pkg)
+		}
+	}
+
+	if got := lookup.GetPackageCount(); got != len(want) {
+		t.Errorf("GetPackageCount() = %d, want %d", got, len(want))
+	}
+	if got := lookup.GetLanguage(); got != "go" {
+		t.Errorf("GetLanguage() = %q, want %q", got, "go")
+	}
+}
@@ -0,0 +1,11 @@
+package resolvers
+
+import "github.com/h0tak88r/confused/pkg/logger"
+
+// LoggerAware is implemented by resolvers that can be pointed at a
+// structured logger, so each registry HTTP check emits one record (event,
+// registry, package, version, status_code, retry, duration_ms, result)
+// instead of an ad-hoc fmt.Printf line.
+type LoggerAware interface {
+	SetLogger(*logger.Logger)
+}
@@ -0,0 +1,64 @@
+package resolvers
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// Metrics aggregates request/latency/cache-hit counters across every
+// resolver sharing it, so a ResolveAll run spanning several ecosystems
+// reports one set of numbers instead of counters scattered per resolver. A
+// nil *Metrics is valid everywhere below and simply discards every
+// recording, so wiring it up is opt-in.
+type Metrics struct {
+	requests     int64
+	cacheHits    int64
+	totalLatency int64 // nanoseconds, accumulated atomically
+}
+
+// MetricsAware is implemented by resolvers that can be pointed at a shared
+// Metrics instance instead of leaving registry-check counts unobserved.
+type MetricsAware interface {
+	SetMetrics(*Metrics)
+}
+
+// RecordRequest records one completed registry HTTP request and its latency.
+func (m *Metrics) RecordRequest(d time.Duration) {
+	if m == nil {
+		return
+	}
+	atomic.AddInt64(&m.requests, 1)
+	atomic.AddInt64(&m.totalLatency, int64(d))
+}
+
+// RecordCacheHit records one registry availability check served from cache
+// instead of a live HTTP request.
+func (m *Metrics) RecordCacheHit() {
+	if m == nil {
+		return
+	}
+	atomic.AddInt64(&m.cacheHits, 1)
+}
+
+// MetricsSnapshot is a point-in-time read of a Metrics' counters.
+type MetricsSnapshot struct {
+	Requests       int64
+	CacheHits      int64
+	AverageLatency time.Duration
+}
+
+// Snapshot returns the current counters as a point-in-time copy.
+func (m *Metrics) Snapshot() MetricsSnapshot {
+	if m == nil {
+		return MetricsSnapshot{}
+	}
+	requests := atomic.LoadInt64(&m.requests)
+	snap := MetricsSnapshot{
+		Requests:  requests,
+		CacheHits: atomic.LoadInt64(&m.cacheHits),
+	}
+	if requests > 0 {
+		snap.AverageLatency = time.Duration(atomic.LoadInt64(&m.totalLatency) / requests)
+	}
+	return snap
+}
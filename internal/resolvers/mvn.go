@@ -10,17 +10,54 @@ import (
 	"strings"
 	"time"
 
-	"github.com/h0tak88r/confused2/internal/types"
+	"github.com/h0tak88r/confused/internal/cache"
+	"github.com/h0tak88r/confused/internal/types"
+	"github.com/h0tak88r/confused/pkg/logger"
+	"github.com/h0tak88r/confused/pkg/registries"
+	"golang.org/x/time/rate"
 )
 
+// defaultMavenRegistry is repo1.maven.org, used when no --registry/
+// --internal-registry flag overrides the "mvn" ecosystem.
+const defaultMavenRegistry = "https://repo1.maven.org/maven2"
+
 // MVNLookup represents a collection of maven packages to be tested for dependency confusion.
 type MVNLookup struct {
-	Packages       []MVNPackage
-	Verbose        bool
-	ctx            context.Context
-	timeout        time.Duration
-	rateLimit      int
-	packageDetails []types.PackageDetail
+	Packages          []MVNPackage
+	Verbose           bool
+	ctx               context.Context
+	timeout           time.Duration
+	rateLimit         int
+	limiter           *rate.Limiter
+	registrySet       *registries.Set
+	log               *logger.Logger
+	workers           int
+	packageDetails    []types.PackageDetail
+	notAvailableCache []string
+	resolved          bool
+	cache             cache.Store
+	cacheTTL          time.Duration
+	httpClient        *http.Client
+	metrics           *Metrics
+}
+
+// SetCache wires store/ttl in so isAvailableInPublic checks are served
+// from cache before falling through to a live registry probe.
+func (n *MVNLookup) SetCache(store cache.Store, ttl time.Duration) {
+	n.cache = store
+	n.cacheTTL = ttl
+}
+
+// SetHTTPClient points this resolver at a shared, instrumented *http.Client
+// (see ResolveAll) instead of building one per registry via Registry.Client.
+func (n *MVNLookup) SetHTTPClient(client *http.Client) {
+	n.httpClient = client
+}
+
+// SetMetrics points this resolver at a shared Metrics instance so its
+// registry checks are counted alongside every other resolver ResolveAll runs.
+func (n *MVNLookup) SetMetrics(m *Metrics) {
+	n.metrics = m
 }
 
 type MVNPackage struct {
@@ -29,6 +66,34 @@ type MVNPackage struct {
 	Version  string
 }
 
+// MavenProject is the subset of a pom.xml <project> element needed to
+// recover its dependencies and any plugin coordinates declared directly or
+// under a build profile.
+type MavenProject struct {
+	Dependencies []MavenDependency `xml:"dependencies>dependency"`
+	Build        MavenBuild        `xml:"build"`
+	Profiles     []MavenProfile    `xml:"profiles>profile"`
+}
+
+// MavenDependency is a single <dependency> (or plugin) coordinate.
+type MavenDependency struct {
+	GroupId    string `xml:"groupId"`
+	ArtifactId string `xml:"artifactId"`
+	Version    string `xml:"version"`
+}
+
+// MavenBuild is the subset of a <build> element needed to recover plugin
+// coordinates, which are resolved from the same registry as dependencies.
+type MavenBuild struct {
+	Plugins []MavenDependency `xml:"plugins>plugin"`
+}
+
+// MavenProfile is a single <profile> element, which may declare its own
+// <build> with its own plugins.
+type MavenProfile struct {
+	Build MavenBuild `xml:"build"`
+}
+
 // NewMVNLookup constructs an `MVNLookup` struct and returns it.
 func NewMVNLookup(verbose bool) types.PackageResolver {
 	return &MVNLookup{
@@ -37,6 +102,7 @@ func NewMVNLookup(verbose bool) types.PackageResolver {
 		ctx:            context.Background(),
 		timeout:        30 * time.Second,
 		rateLimit:      100,
+		workers:        defaultWorkers,
 		packageDetails: []types.PackageDetail{},
 	}
 }
@@ -91,57 +157,177 @@ func (n *MVNLookup) ReadPackagesFromFile(filename string) error {
 //
 // Returns a slice of strings with any npm packages not in the public npm package repository
 func (n *MVNLookup) PackagesNotInPublic() []string {
-	notavail := []string{}
-	for _, pkg := range n.Packages {
-		if !n.isAvailableInPublic(pkg, 0) {
-			notavail = append(notavail, pkg.Group+"/"+pkg.Artifact)
-		}
+	if n.resolved {
+		return n.notAvailableCache
 	}
+	notavail, _ := n.resolve(context.Background())
 	return notavail
 }
 
-// isAvailableInPublic determines if an npm package exists in the public npm package repository.
-//
-// Returns true if the package exists in the public npm package repository.
-func (n *MVNLookup) isAvailableInPublic(pkg MVNPackage, retry int) bool {
-	if retry > 3 {
-		fmt.Printf(" [W] Maximum number of retries exhausted for package: %s\n", pkg.Group)
+// ResolveWithContext behaves like PackagesNotInPublic but aborts as soon as
+// ctx is cancelled and honors the rate limiter set via SetLimiter, so a
+// SIGINT during an org scan stops in-flight registry probes instead of
+// running to completion.
+func (n *MVNLookup) ResolveWithContext(ctx context.Context) error {
+	n.ctx = ctx
+	notavail, err := n.resolve(ctx)
+	if err != nil {
+		return err
+	}
+	n.notAvailableCache = notavail
+	n.resolved = true
+	return nil
+}
+
+// resolve dispatches an isConfused check per package across n.workers
+// goroutines, rate-limited by n.limiter, returning the confused packages in
+// the same order as n.Packages.
+func (n *MVNLookup) resolve(ctx context.Context) ([]string, error) {
+	indices, err := resolveConcurrently(ctx, len(n.Packages), n.workers, func(ctx context.Context, i int) (bool, error) {
+		if err := waitForToken(ctx, n.limiter); err != nil {
+			return false, err
+		}
+		return n.isConfused(n.Packages[i]), nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	notavail := make([]string, 0, len(indices))
+	for _, i := range indices {
+		pkg := n.Packages[i]
+		notavail = append(notavail, pkg.Group+"/"+pkg.Artifact)
+	}
+	return notavail, nil
+}
+
+// SetLimiter points this resolver at a shared rate limiter.
+func (n *MVNLookup) SetLimiter(limiter *rate.Limiter) {
+	n.limiter = limiter
+}
+
+// SetWorkers points this resolver at the configured --workers concurrency
+// for its registry probes.
+func (n *MVNLookup) SetWorkers(workers int) {
+	if workers > 0 {
+		n.workers = workers
+	}
+}
+
+// SetRegistries points this resolver at the configured public/internal Maven
+// mirrors, so a package only counts as "public" if it's found in one of the
+// configured public registries (defaulting to repo1.maven.org), and only
+// counts as confused if it's also present in a configured internal one.
+func (n *MVNLookup) SetRegistries(regs *registries.Set) {
+	n.registrySet = regs
+}
+
+// SetLogger points this resolver at a structured logger, so each registry
+// HTTP check emits one event record instead of an ad-hoc fmt.Printf line.
+func (n *MVNLookup) SetLogger(log *logger.Logger) {
+	n.log = log
+}
+
+// logCheck emits a structured "registry_check" event for a single HTTP
+// check. If no logger was wired via SetLogger, it falls back to printing a
+// human-readable line so the resolver still works when used standalone.
+func (n *MVNLookup) logCheck(reg registries.Registry, pkg MVNPackage, retry int, statusCode int, duration time.Duration, result string) {
+	if n.log == nil {
+		fmt.Printf(" [%s] %s (registry=%s status=%d retry=%d)\n", result, pkg.Group, reg.URL, statusCode, retry)
+		return
+	}
+	n.log.WithFields(map[string]interface{}{
+		"event":       "registry_check",
+		"registry":    reg.URL,
+		"package":     pkg.Group + ":" + pkg.Artifact,
+		"version":     pkg.Version,
+		"status_code": statusCode,
+		"retry":       retry,
+		"duration_ms": duration.Milliseconds(),
+		"result":      result,
+	}).Debug("mvn registry check")
+}
+
+// isConfused reports whether pkg is absent from every configured public
+// Maven registry and, when an internal allowlist was configured, present (or
+// expected, if no internal allowlist was configured) in one of those.
+func (n *MVNLookup) isConfused(pkg MVNPackage) bool {
+	if n.isAvailableInPublic(pkg) {
 		return false
 	}
+	if len(n.registrySet.Internal("mvn")) == 0 {
+		return true
+	}
+	return n.isAvailableInInternal(pkg)
+}
+
+// isAvailableInPublic determines if a maven package exists in any of the
+// configured public Maven registries (repo1.maven.org by default).
+//
+// Returns true if the package exists in a public Maven registry.
+func (n *MVNLookup) isAvailableInPublic(pkg MVNPackage) bool {
 	if pkg.Group == "" {
 		return true
 	}
+	pkgname := pkg.Group + ":" + pkg.Artifact
+	if available, ok := cachedAvailability(n.cache, "mvn", pkgname, pkg.Version); ok {
+		n.metrics.RecordCacheHit()
+		return available
+	}
+	available := false
+	for _, reg := range n.registrySet.Public("mvn", defaultMavenRegistry) {
+		if n.checkRegistry(reg, pkg) {
+			available = true
+			break
+		}
+	}
+	setCachedAvailability(n.cache, n.cacheTTL, "mvn", pkgname, pkg.Version, available)
+	return available
+}
 
-	group := strings.Replace(pkg.Group, ".", "/", -1)
-	if n.Verbose {
-		fmt.Print("Checking: https://repo1.maven.org/maven2/" + group + "/ ")
+// isAvailableInInternal determines if a maven package exists in any of the
+// registries configured as "internal" via --internal-registry.
+func (n *MVNLookup) isAvailableInInternal(pkg MVNPackage) bool {
+	for _, reg := range n.registrySet.Internal("mvn") {
+		if n.checkRegistry(reg, pkg) {
+			return true
+		}
 	}
-	resp, err := http.Get("https://repo1.maven.org/maven2/" + group + "/")
+	return false
+}
+
+// checkRegistry determines if a maven package exists in a single registry.
+// 429/5xx are retried with backoff centrally by doRegistryGET.
+func (n *MVNLookup) checkRegistry(reg registries.Registry, pkg MVNPackage) bool {
+	group := strings.Replace(pkg.Group, ".", "/", -1)
+	url := reg.URL + "/" + group + "/"
+	resp, duration, retries, err := doRegistryGET(reg, url, n.timeout, n.httpClient, n.metrics)
 	if err != nil {
-		fmt.Printf(" [W] Error when trying to request https://repo1.maven.org/maven2/"+group+"/ : %s\n", err)
+		n.logCheck(reg, pkg, retries, 0, duration, fmt.Sprintf("request_error: %s", err))
 		return false
 	}
 	defer resp.Body.Close()
-	if n.Verbose {
-		fmt.Printf("%s\n", resp.Status)
+	if resp.StatusCode == http.StatusTooManyRequests {
+		n.logCheck(reg, pkg, retries, resp.StatusCode, duration, "retries_exhausted")
+		return false
 	}
 	if resp.StatusCode == http.StatusOK {
 		npmResp := NpmResponse{}
 		body, _ := ioutil.ReadAll(resp.Body)
 		_ = json.Unmarshal(body, &npmResp)
 		if npmResp.NotAvailable() {
-			if n.Verbose {
-				fmt.Printf("[W] Package %s was found, but all its versions are unpublished, making anyone able to takeover the namespace.\n", pkg.Group)
-			}
+			n.logCheck(reg, pkg, retries, resp.StatusCode, duration, "unpublished")
+			return false
+		}
+		if matched, ok, _ := CheckVersionAgainstFetch(pkg.Version, func() ([]string, error) {
+			return fetchMavenVersions(reg, pkg.Group, pkg.Artifact)
+		}); ok && !matched {
+			n.logCheck(reg, pkg, retries, resp.StatusCode, duration, "version_not_published")
 			return false
 		}
+		n.logCheck(reg, pkg, retries, resp.StatusCode, duration, "available")
 		return true
-	} else if resp.StatusCode == 429 {
-		fmt.Printf(" [!] Server responded with 429 (Too many requests), throttling and retrying...\n")
-		time.Sleep(10 * time.Second)
-		retry = retry + 1
-		return n.isAvailableInPublic(pkg, retry)
 	}
+	n.logCheck(reg, pkg, retries, resp.StatusCode, duration, "not_available")
 	return false
 }
 
@@ -178,10 +364,16 @@ func (m *MVNLookup) GetPackageDetails() []types.PackageDetail {
 	return m.packageDetails
 }
 
-// buildPackageDetails builds detailed package information
+// buildPackageDetails builds detailed package information.
+//
+// Note: unlike composer.go/pip.go, this doesn't cross-check artifact
+// checksums against a lockfile pin - MVNLookup only reads pom.xml
+// coordinates, which carry no checksum, and doesn't parse `mvn
+// dependency:tree` output or the per-artifact .sha1 files Maven Central
+// publishes alongside each jar.
 func (m *MVNLookup) buildPackageDetails() {
 	m.packageDetails = []types.PackageDetail{}
-	
+
 	for _, pkg := range m.Packages {
 		detail := types.PackageDetail{
 			Name:    pkg.Group + ":" + pkg.Artifact,
@@ -193,13 +385,23 @@ func (m *MVNLookup) buildPackageDetails() {
 				"version":  pkg.Version,
 			},
 		}
-		
+
 		// Check if package is vulnerable
-		if !m.isAvailableInPublic(pkg, 0) {
+		if m.isConfused(pkg) {
 			detail.Vulnerable = true
 			detail.Reason = "Package not available in public Maven repository"
 		}
-		
+
+		for _, reg := range m.registrySet.Public("mvn", defaultMavenRegistry) {
+			if matched, ok, versions := CheckVersionAgainstFetch(pkg.Version, func() ([]string, error) {
+				return fetchMavenVersions(reg, pkg.Group, pkg.Artifact)
+			}); ok {
+				detail.Metadata["version_matched"] = matched
+				detail.Metadata["available_versions"] = versions
+				break
+			}
+		}
+
 		m.packageDetails = append(m.packageDetails, detail)
 	}
-}
\ No newline at end of file
+}
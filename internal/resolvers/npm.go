@@ -0,0 +1,402 @@
+package resolvers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/h0tak88r/confused/internal/cache"
+	"github.com/h0tak88r/confused/internal/types"
+	"github.com/h0tak88r/confused/pkg/logger"
+	"github.com/h0tak88r/confused/pkg/registries"
+	"golang.org/x/time/rate"
+)
+
+// defaultNpmRegistry is registry.npmjs.org, used when no --registry/
+// --internal-registry flag overrides the "npm" ecosystem.
+const defaultNpmRegistry = "https://registry.npmjs.org"
+
+// NpmResponse mirrors the bits of an npm registry package document needed
+// to recognize whether a name is actually published. npm answers some
+// unpublished/deleted scoped package names with a 200 status and a
+// {"error":"Not found"} body instead of a 404, so status code alone isn't
+// reliable.
+type NpmResponse struct {
+	Name     string                     `json:"name"`
+	Error    string                     `json:"error"`
+	Versions map[string]json.RawMessage `json:"versions"`
+}
+
+// NotAvailable reports whether this response indicates the package isn't
+// actually published, despite a 200 status.
+func (r NpmResponse) NotAvailable() bool {
+	return r.Error != ""
+}
+
+// NpmPackage is a single dependency parsed from package.json.
+type NpmPackage struct {
+	Name    string
+	Version string
+}
+
+// npmPackageJSON represents the subset of package.json fields needed to
+// recover its direct dependencies.
+type npmPackageJSON struct {
+	Dependencies    map[string]string `json:"dependencies,omitempty"`
+	DevDependencies map[string]string `json:"devDependencies,omitempty"`
+}
+
+// NPMLookup represents a collection of npm packages to be tested for dependency confusion.
+type NPMLookup struct {
+	Packages          []NpmPackage
+	Verbose           bool
+	ctx               context.Context
+	timeout           time.Duration
+	rateLimit         int
+	limiter           *rate.Limiter
+	registrySet       *registries.Set
+	log               *logger.Logger
+	workers           int
+	packageDetails    []types.PackageDetail
+	notAvailableCache []string
+	resolved          bool
+	cache             cache.Store
+	cacheTTL          time.Duration
+	httpClient        *http.Client
+	metrics           *Metrics
+}
+
+// SetCache wires store/ttl in so isAvailableInPublic checks are served
+// from cache before falling through to a live registry probe.
+func (n *NPMLookup) SetCache(store cache.Store, ttl time.Duration) {
+	n.cache = store
+	n.cacheTTL = ttl
+}
+
+// SetHTTPClient points this resolver at a shared, instrumented *http.Client
+// (see ResolveAll) instead of building one per registry via Registry.Client.
+func (n *NPMLookup) SetHTTPClient(client *http.Client) {
+	n.httpClient = client
+}
+
+// SetMetrics points this resolver at a shared Metrics instance so its
+// registry checks are counted alongside every other resolver ResolveAll runs.
+func (n *NPMLookup) SetMetrics(m *Metrics) {
+	n.metrics = m
+}
+
+// NewNPMLookup constructs an `NPMLookup` struct and returns it.
+func NewNPMLookup(verbose bool) types.PackageResolver {
+	return &NPMLookup{
+		Packages:       []NpmPackage{},
+		Verbose:        verbose,
+		ctx:            context.Background(),
+		timeout:        30 * time.Second,
+		rateLimit:      100,
+		workers:        defaultWorkers,
+		packageDetails: []types.PackageDetail{},
+	}
+}
+
+// ReadPackagesFromFile reads package information from an npm package.json file.
+//
+// Returns any errors encountered
+func (n *NPMLookup) ReadPackagesFromFile(filename string) error {
+	rawfile, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return err
+	}
+
+	data := npmPackageJSON{}
+	if err := json.Unmarshal(rawfile, &data); err != nil {
+		if n.Verbose {
+			fmt.Printf(" [W] Non-fatal issue encountered while reading %s : %s\n", filename, err)
+		}
+		return nil
+	}
+	for pkgname, pkgversion := range data.Dependencies {
+		n.Packages = append(n.Packages, NpmPackage{Name: pkgname, Version: pkgversion})
+	}
+	for pkgname, pkgversion := range data.DevDependencies {
+		n.Packages = append(n.Packages, NpmPackage{Name: pkgname, Version: pkgversion})
+	}
+	return nil
+}
+
+// PackagesNotInPublic determines if an npm package does not exist in the public npm package repository.
+//
+// Returns a slice of strings with any npm packages not in the public npm package repository
+func (n *NPMLookup) PackagesNotInPublic() []string {
+	if n.resolved {
+		return n.notAvailableCache
+	}
+	notavail, _ := n.resolve(context.Background())
+	return notavail
+}
+
+// ResolveWithContext behaves like PackagesNotInPublic but aborts as soon as
+// ctx is cancelled and honors the rate limiter set via SetLimiter, so a
+// SIGINT during an org scan stops in-flight registry probes instead of
+// running to completion.
+func (n *NPMLookup) ResolveWithContext(ctx context.Context) error {
+	n.ctx = ctx
+	notavail, err := n.resolve(ctx)
+	if err != nil {
+		return err
+	}
+	n.notAvailableCache = notavail
+	n.resolved = true
+	return nil
+}
+
+// resolve dispatches an isConfused check per package across n.workers
+// goroutines, rate-limited by n.limiter, returning the confused packages in
+// the same order as n.Packages.
+func (n *NPMLookup) resolve(ctx context.Context) ([]string, error) {
+	indices, err := resolveConcurrently(ctx, len(n.Packages), n.workers, func(ctx context.Context, i int) (bool, error) {
+		if err := waitForToken(ctx, n.limiter); err != nil {
+			return false, err
+		}
+		return n.isConfused(n.Packages[i]), nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	notavail := make([]string, 0, len(indices))
+	for _, i := range indices {
+		notavail = append(notavail, n.Packages[i].Name)
+	}
+	return notavail, nil
+}
+
+// SetLimiter points this resolver at a shared rate limiter.
+func (n *NPMLookup) SetLimiter(limiter *rate.Limiter) {
+	n.limiter = limiter
+}
+
+// SetWorkers points this resolver at the configured --workers concurrency
+// for its registry probes.
+func (n *NPMLookup) SetWorkers(workers int) {
+	if workers > 0 {
+		n.workers = workers
+	}
+}
+
+// SetRegistries points this resolver at the configured public/internal npm
+// mirrors, so a package only counts as "public" if it's found in one of the
+// configured public registries (defaulting to registry.npmjs.org), and only
+// counts as confused if it's also present in a configured internal one.
+func (n *NPMLookup) SetRegistries(regs *registries.Set) {
+	n.registrySet = regs
+}
+
+// SetLogger points this resolver at a structured logger, so each registry
+// HTTP check emits one event record instead of an ad-hoc fmt.Printf line.
+func (n *NPMLookup) SetLogger(log *logger.Logger) {
+	n.log = log
+}
+
+// logCheck emits a structured "registry_check" event for a single HTTP
+// check. If no logger was wired via SetLogger, it falls back to printing a
+// human-readable line so the resolver still works when used standalone.
+func (n *NPMLookup) logCheck(reg registries.Registry, pkg NpmPackage, retry int, statusCode int, duration time.Duration, result string) {
+	if n.log == nil {
+		fmt.Printf(" [%s] %s (registry=%s status=%d retry=%d)\n", result, pkg.Name, reg.URL, statusCode, retry)
+		return
+	}
+	n.log.WithFields(map[string]interface{}{
+		"event":       "registry_check",
+		"registry":    reg.URL,
+		"package":     pkg.Name,
+		"version":     pkg.Version,
+		"status_code": statusCode,
+		"retry":       retry,
+		"duration_ms": duration.Milliseconds(),
+		"result":      result,
+	}).Debug("npm registry check")
+}
+
+// isConfused reports whether pkg is absent from every configured public npm
+// registry and, when an internal allowlist was configured, present (or
+// expected, if no internal allowlist was configured) in one of those.
+func (n *NPMLookup) isConfused(pkg NpmPackage) bool {
+	if n.isAvailableInPublic(pkg) {
+		return false
+	}
+	if len(n.registrySet.Internal("npm")) == 0 {
+		return true
+	}
+	return n.isAvailableInInternal(pkg)
+}
+
+// isAvailableInPublic determines if an npm package exists in any of the
+// configured public npm registries (registry.npmjs.org by default).
+//
+// Returns true if the package exists in a public npm registry.
+func (n *NPMLookup) isAvailableInPublic(pkg NpmPackage) bool {
+	if available, ok := cachedAvailability(n.cache, "npm", pkg.Name, pkg.Version); ok {
+		n.metrics.RecordCacheHit()
+		return available
+	}
+	available := false
+	for _, reg := range n.registrySet.Public("npm", defaultNpmRegistry) {
+		if n.checkRegistry(reg, pkg) {
+			available = true
+			break
+		}
+	}
+	setCachedAvailability(n.cache, n.cacheTTL, "npm", pkg.Name, pkg.Version, available)
+	return available
+}
+
+// isAvailableInInternal determines if an npm package exists in any of the
+// registries configured as "internal" via --internal-registry.
+func (n *NPMLookup) isAvailableInInternal(pkg NpmPackage) bool {
+	for _, reg := range n.registrySet.Internal("npm") {
+		if n.checkRegistry(reg, pkg) {
+			return true
+		}
+	}
+	return false
+}
+
+// checkRegistry determines if an npm package exists in a single registry.
+// 429/5xx are retried with backoff centrally by doRegistryGET.
+func (n *NPMLookup) checkRegistry(reg registries.Registry, pkg NpmPackage) bool {
+	url := reg.URL + "/" + npmEscapeName(pkg.Name)
+	resp, duration, retries, err := doRegistryGET(reg, url, n.timeout, n.httpClient, n.metrics)
+	if err != nil {
+		n.logCheck(reg, pkg, retries, 0, duration, fmt.Sprintf("request_error: %s", err))
+		return false
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		n.logCheck(reg, pkg, retries, resp.StatusCode, duration, "not_available")
+		return false
+	}
+	body, _ := ioutil.ReadAll(resp.Body)
+	npmResp := NpmResponse{}
+	_ = json.Unmarshal(body, &npmResp)
+	if npmResp.NotAvailable() {
+		n.logCheck(reg, pkg, retries, resp.StatusCode, duration, "unpublished")
+		return false
+	}
+	if matched, ok, _ := CheckVersionAgainstFetch(pkg.Version, func() ([]string, error) {
+		return fetchNpmVersions(reg, pkg.Name)
+	}); ok && !matched {
+		n.logCheck(reg, pkg, retries, resp.StatusCode, duration, "version_not_published")
+		return false
+	}
+	n.logCheck(reg, pkg, retries, resp.StatusCode, duration, "available")
+	return true
+}
+
+// npmEscapeName URL-encodes the "/" in a scoped package name (e.g.
+// "@scope/name" -> "@scope%2Fname"), matching how the npm registry expects
+// scoped package paths.
+func npmEscapeName(name string) string {
+	return strings.Replace(name, "/", "%2F", 1)
+}
+
+// GetPackageCount returns the number of packages
+func (n *NPMLookup) GetPackageCount() int {
+	return len(n.Packages)
+}
+
+// GetLanguage returns the language name
+func (n *NPMLookup) GetLanguage() string {
+	return "npm"
+}
+
+// SetContext sets the context for the resolver
+func (n *NPMLookup) SetContext(ctx context.Context) {
+	n.ctx = ctx
+}
+
+// SetTimeout sets the timeout for requests
+func (n *NPMLookup) SetTimeout(timeout time.Duration) {
+	n.timeout = timeout
+}
+
+// SetRateLimit sets the rate limit for requests
+func (n *NPMLookup) SetRateLimit(rate int) {
+	n.rateLimit = rate
+}
+
+// GetPackageDetails returns detailed information about packages
+func (n *NPMLookup) GetPackageDetails() []types.PackageDetail {
+	if len(n.packageDetails) == 0 {
+		n.buildPackageDetails()
+	}
+	return n.packageDetails
+}
+
+// buildPackageDetails builds detailed package information
+func (n *NPMLookup) buildPackageDetails() {
+	n.packageDetails = []types.PackageDetail{}
+
+	for _, pkg := range n.Packages {
+		detail := types.PackageDetail{
+			Name:    pkg.Name,
+			Version: pkg.Version,
+			Type:    "dependency",
+			Metadata: map[string]interface{}{
+				"original_name": pkg.Name,
+			},
+		}
+
+		if n.isConfused(pkg) {
+			detail.Vulnerable = true
+			detail.Reason = "Package not available in public npm registry"
+		}
+
+		for _, reg := range n.registrySet.Public("npm", defaultNpmRegistry) {
+			if matched, ok, versions := CheckVersionAgainstFetch(pkg.Version, func() ([]string, error) {
+				return fetchNpmVersions(reg, pkg.Name)
+			}); ok {
+				detail.Metadata["version_matched"] = matched
+				detail.Metadata["available_versions"] = versions
+			}
+			break
+		}
+
+		n.packageDetails = append(n.packageDetails, detail)
+	}
+}
+
+// fetchNpmVersions fetches the published versions for pkgname from reg's
+// package document, whose top-level "versions" object is keyed by version
+// string.
+func fetchNpmVersions(reg registries.Registry, pkgname string) ([]string, error) {
+	url := reg.URL + "/" + npmEscapeName(pkgname)
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+	reg.ApplyAuth(req)
+	resp, err := reg.Client(0).Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("npm registry returned %s for %s", resp.Status, url)
+	}
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	var data NpmResponse
+	if err := json.Unmarshal(body, &data); err != nil {
+		return nil, err
+	}
+	versions := make([]string, 0, len(data.Versions))
+	for v := range data.Versions {
+		versions = append(versions, v)
+	}
+	return versions, nil
+}
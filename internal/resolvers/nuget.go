@@ -0,0 +1,426 @@
+package resolvers
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/h0tak88r/confused/internal/cache"
+	"github.com/h0tak88r/confused/internal/types"
+	"github.com/h0tak88r/confused/pkg/logger"
+	"github.com/h0tak88r/confused/pkg/registries"
+	"golang.org/x/time/rate"
+)
+
+// defaultNuGetRegistry is nuget.org's flat container API, used when no
+// --registry/--internal-registry flag overrides the "nuget" ecosystem.
+const defaultNuGetRegistry = "https://api.nuget.org/v3-flatcontainer"
+
+// csprojProject mirrors the bits of a .csproj MSBuild project file needed
+// to recover its PackageReference entries.
+type csprojProject struct {
+	ItemGroups []struct {
+		PackageReferences []struct {
+			Include string `xml:"Include,attr"`
+			Version string `xml:"Version,attr"`
+		} `xml:"PackageReference"`
+	} `xml:"ItemGroup"`
+}
+
+// packagesConfig mirrors a legacy NuGet packages.config file.
+type packagesConfig struct {
+	Packages []struct {
+		ID      string `xml:"id,attr"`
+		Version string `xml:"version,attr"`
+	} `xml:"package"`
+}
+
+// NuGetPackage is a single package requirement parsed from a .csproj or
+// packages.config file.
+type NuGetPackage struct {
+	ID      string
+	Version string
+}
+
+// NuGetLookup represents a collection of NuGet packages to be tested for dependency confusion.
+type NuGetLookup struct {
+	Packages          []NuGetPackage
+	Verbose           bool
+	ctx               context.Context
+	timeout           time.Duration
+	rateLimit         int
+	limiter           *rate.Limiter
+	registrySet       *registries.Set
+	log               *logger.Logger
+	workers           int
+	packageDetails    []types.PackageDetail
+	notAvailableCache []string
+	resolved          bool
+	cache             cache.Store
+	cacheTTL          time.Duration
+	httpClient        *http.Client
+	metrics           *Metrics
+}
+
+// NewNuGetLookup constructs a `NuGetLookup` struct and returns it.
+func NewNuGetLookup(verbose bool) types.PackageResolver {
+	return &NuGetLookup{
+		Packages:       []NuGetPackage{},
+		Verbose:        verbose,
+		ctx:            context.Background(),
+		timeout:        30 * time.Second,
+		rateLimit:      100,
+		workers:        defaultWorkers,
+		packageDetails: []types.PackageDetail{},
+	}
+}
+
+// SetCache wires store/ttl in so isAvailableInPublic checks are served
+// from cache before falling through to a live registry probe.
+func (n *NuGetLookup) SetCache(store cache.Store, ttl time.Duration) {
+	n.cache = store
+	n.cacheTTL = ttl
+}
+
+// SetHTTPClient points this resolver at a shared, instrumented *http.Client
+// (see ResolveAll) instead of building one per registry via Registry.Client.
+func (n *NuGetLookup) SetHTTPClient(client *http.Client) {
+	n.httpClient = client
+}
+
+// SetMetrics points this resolver at a shared Metrics instance so its
+// registry checks are counted alongside every other resolver ResolveAll runs.
+func (n *NuGetLookup) SetMetrics(m *Metrics) {
+	n.metrics = m
+}
+
+// ReadPackagesFromFile reads package information from a .csproj or
+// packages.config file, picking the parser by file extension.
+//
+// Returns any errors encountered
+func (n *NuGetLookup) ReadPackagesFromFile(filename string) error {
+	rawfile, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return err
+	}
+
+	if n.Verbose {
+		fmt.Print("Checking: filename: " + filename + "\n")
+	}
+
+	if len(rawfile) < 10 {
+		if n.Verbose {
+			fmt.Printf("Skipping empty or too small NuGet manifest: %s\n", filename)
+		}
+		return nil
+	}
+
+	if strings.EqualFold(filepathExt(filename), ".config") {
+		var config packagesConfig
+		if err := xml.Unmarshal(rawfile, &config); err != nil {
+			if n.Verbose {
+				fmt.Printf("Warning: unable to parse packages.config %s: %s\n", filename, err)
+			}
+			return nil
+		}
+		for _, pkg := range config.Packages {
+			n.Packages = append(n.Packages, NuGetPackage{ID: pkg.ID, Version: pkg.Version})
+		}
+		return nil
+	}
+
+	var project csprojProject
+	if err := xml.Unmarshal(rawfile, &project); err != nil {
+		if n.Verbose {
+			fmt.Printf("Warning: unable to parse csproj %s: %s\n", filename, err)
+		}
+		return nil
+	}
+	for _, group := range project.ItemGroups {
+		for _, ref := range group.PackageReferences {
+			n.Packages = append(n.Packages, NuGetPackage{ID: ref.Include, Version: ref.Version})
+		}
+	}
+
+	return nil
+}
+
+// filepathExt returns the lowercase extension of filename, including the
+// leading dot.
+func filepathExt(filename string) string {
+	idx := strings.LastIndex(filename, ".")
+	if idx == -1 {
+		return ""
+	}
+	return filename[idx:]
+}
+
+// PackagesNotInPublic determines if a NuGet package does not exist in the
+// configured public NuGet feed.
+//
+// Returns a slice of strings with any NuGet packages not in the public feed
+func (n *NuGetLookup) PackagesNotInPublic() []string {
+	if n.resolved {
+		return n.notAvailableCache
+	}
+	notavail, _ := n.resolve(context.Background())
+	return notavail
+}
+
+// ResolveWithContext behaves like PackagesNotInPublic but aborts as soon as
+// ctx is cancelled and honors the rate limiter set via SetLimiter, so a
+// SIGINT during an org scan stops in-flight registry probes instead of
+// running to completion.
+func (n *NuGetLookup) ResolveWithContext(ctx context.Context) error {
+	n.ctx = ctx
+	notavail, err := n.resolve(ctx)
+	if err != nil {
+		return err
+	}
+	n.notAvailableCache = notavail
+	n.resolved = true
+	return nil
+}
+
+// resolve dispatches an isConfused check per package across n.workers
+// goroutines, rate-limited by n.limiter, returning the confused packages in
+// the same order as n.Packages.
+func (n *NuGetLookup) resolve(ctx context.Context) ([]string, error) {
+	indices, err := resolveConcurrently(ctx, len(n.Packages), n.workers, func(ctx context.Context, i int) (bool, error) {
+		if err := waitForToken(ctx, n.limiter); err != nil {
+			return false, err
+		}
+		return n.isConfused(n.Packages[i]), nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	notavail := make([]string, 0, len(indices))
+	for _, i := range indices {
+		notavail = append(notavail, n.Packages[i].ID)
+	}
+	return notavail, nil
+}
+
+// SetLimiter points this resolver at a shared rate limiter.
+func (n *NuGetLookup) SetLimiter(limiter *rate.Limiter) {
+	n.limiter = limiter
+}
+
+// SetWorkers points this resolver at the configured --workers concurrency
+// for its registry probes.
+func (n *NuGetLookup) SetWorkers(workers int) {
+	if workers > 0 {
+		n.workers = workers
+	}
+}
+
+// SetRegistries points this resolver at the configured public/internal
+// NuGet feeds, so a package only counts as "public" if it's found in one of
+// the configured public feeds (defaulting to nuget.org), and only counts as
+// confused if it's also present in a configured internal one.
+func (n *NuGetLookup) SetRegistries(regs *registries.Set) {
+	n.registrySet = regs
+}
+
+// SetLogger points this resolver at a structured logger, so each registry
+// HTTP check emits one event record instead of an ad-hoc fmt.Printf line.
+func (n *NuGetLookup) SetLogger(log *logger.Logger) {
+	n.log = log
+}
+
+// logCheck emits a structured "registry_check" event for a single HTTP
+// check. If no logger was wired via SetLogger, it falls back to printing a
+// human-readable line so the resolver still works when used standalone.
+func (n *NuGetLookup) logCheck(reg registries.Registry, pkg NuGetPackage, retry int, statusCode int, duration time.Duration, result string) {
+	if n.log == nil {
+		fmt.Printf(" [%s] %s (registry=%s status=%d retry=%d)\n", result, pkg.ID, reg.URL, statusCode, retry)
+		return
+	}
+	n.log.WithFields(map[string]interface{}{
+		"event":       "registry_check",
+		"registry":    reg.URL,
+		"package":     pkg.ID,
+		"version":     pkg.Version,
+		"status_code": statusCode,
+		"retry":       retry,
+		"duration_ms": duration.Milliseconds(),
+		"result":      result,
+	}).Debug("nuget registry check")
+}
+
+// isConfused reports whether pkg is absent from every configured public
+// NuGet feed and, when an internal allowlist was configured, present (or
+// expected, if no internal allowlist was configured) in one of those.
+func (n *NuGetLookup) isConfused(pkg NuGetPackage) bool {
+	if n.isAvailableInPublic(pkg) {
+		return false
+	}
+	if len(n.registrySet.Internal("nuget")) == 0 {
+		return true
+	}
+	return n.isAvailableInInternal(pkg)
+}
+
+// isAvailableInPublic determines if a NuGet package exists in any of the
+// configured public feeds (nuget.org by default).
+//
+// Returns true if the package exists in a public feed.
+func (n *NuGetLookup) isAvailableInPublic(pkg NuGetPackage) bool {
+	if available, ok := cachedAvailability(n.cache, "nuget", pkg.ID, pkg.Version); ok {
+		n.metrics.RecordCacheHit()
+		return available
+	}
+	available := false
+	for _, reg := range n.registrySet.Public("nuget", defaultNuGetRegistry) {
+		if n.checkRegistry(reg, pkg) {
+			available = true
+			break
+		}
+	}
+	setCachedAvailability(n.cache, n.cacheTTL, "nuget", pkg.ID, pkg.Version, available)
+	return available
+}
+
+// isAvailableInInternal determines if a NuGet package exists in any of the
+// registries configured as "internal" via --internal-registry.
+func (n *NuGetLookup) isAvailableInInternal(pkg NuGetPackage) bool {
+	for _, reg := range n.registrySet.Internal("nuget") {
+		if n.checkRegistry(reg, pkg) {
+			return true
+		}
+	}
+	return false
+}
+
+// checkRegistry determines if a NuGet package exists in a single feed, via
+// its flat-container API
+// (https://api.nuget.org/v3-flatcontainer/<id-lowercase>/index.json), which
+// 404s for a package ID that's never been published. 429/5xx are retried
+// with backoff centrally by doRegistryGET.
+func (n *NuGetLookup) checkRegistry(reg registries.Registry, pkg NuGetPackage) bool {
+	url := reg.URL + "/" + strings.ToLower(pkg.ID) + "/index.json"
+	resp, duration, retries, err := doRegistryGET(reg, url, n.timeout, n.httpClient, n.metrics)
+	if err != nil {
+		n.logCheck(reg, pkg, retries, 0, duration, fmt.Sprintf("request_error: %s", err))
+		return false
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		n.logCheck(reg, pkg, retries, resp.StatusCode, duration, "not_available")
+		return false
+	}
+	if matched, ok, _ := CheckVersionAgainstFetch(pkg.Version, func() ([]string, error) {
+		return fetchNuGetVersions(reg, pkg.ID)
+	}); ok && !matched {
+		n.logCheck(reg, pkg, retries, resp.StatusCode, duration, "version_not_published")
+		return false
+	}
+	n.logCheck(reg, pkg, retries, resp.StatusCode, duration, "available")
+	return true
+}
+
+// GetPackageCount returns the number of packages
+func (n *NuGetLookup) GetPackageCount() int {
+	return len(n.Packages)
+}
+
+// GetLanguage returns the language name
+func (n *NuGetLookup) GetLanguage() string {
+	return "nuget"
+}
+
+// SetContext sets the context for the resolver
+func (n *NuGetLookup) SetContext(ctx context.Context) {
+	n.ctx = ctx
+}
+
+// SetTimeout sets the timeout for requests
+func (n *NuGetLookup) SetTimeout(timeout time.Duration) {
+	n.timeout = timeout
+}
+
+// SetRateLimit sets the rate limit for requests
+func (n *NuGetLookup) SetRateLimit(rate int) {
+	n.rateLimit = rate
+}
+
+// GetPackageDetails returns detailed information about packages
+func (n *NuGetLookup) GetPackageDetails() []types.PackageDetail {
+	if len(n.packageDetails) == 0 {
+		n.buildPackageDetails()
+	}
+	return n.packageDetails
+}
+
+// buildPackageDetails builds detailed package information.
+func (n *NuGetLookup) buildPackageDetails() {
+	n.packageDetails = []types.PackageDetail{}
+
+	for _, pkg := range n.Packages {
+		detail := types.PackageDetail{
+			Name:    pkg.ID,
+			Version: pkg.Version,
+			Type:    "dependency",
+			Metadata: map[string]interface{}{
+				"original_name": pkg.ID,
+			},
+		}
+
+		if n.isConfused(pkg) {
+			detail.Vulnerable = true
+			detail.Reason = "Package not available in public NuGet feed"
+		}
+
+		for _, reg := range n.registrySet.Public("nuget", defaultNuGetRegistry) {
+			if matched, ok, versions := CheckVersionAgainstFetch(pkg.Version, func() ([]string, error) {
+				return fetchNuGetVersions(reg, pkg.ID)
+			}); ok {
+				detail.Metadata["version_matched"] = matched
+				detail.Metadata["available_versions"] = versions
+			}
+			break
+		}
+
+		n.packageDetails = append(n.packageDetails, detail)
+	}
+}
+
+// nuGetFlatContainerIndex mirrors the flat-container API's index.json
+// response, listing a package ID's published versions.
+type nuGetFlatContainerIndex struct {
+	Versions []string `json:"versions"`
+}
+
+// fetchNuGetVersions fetches the published versions for id from reg's
+// flat-container index.
+func fetchNuGetVersions(reg registries.Registry, id string) ([]string, error) {
+	url := reg.URL + "/" + strings.ToLower(id) + "/index.json"
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+	reg.ApplyAuth(req)
+	resp, err := reg.Client(0).Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("flat-container index not found at %s (status %s)", url, resp.Status)
+	}
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	var data nuGetFlatContainerIndex
+	if err := json.Unmarshal(body, &data); err != nil {
+		return nil, err
+	}
+	return data.Versions, nil
+}
@@ -0,0 +1,28 @@
+package resolvers
+
+import "testing"
+
+func TestNuGetLookup_ReadPackagesFromFile_Csproj(t *testing.T) {
+	lookup := NewNuGetLookup(false).(*NuGetLookup)
+
+	if err := lookup.ReadPackagesFromFile("testdata/sample.csproj"); err != nil {
+		t.Fatalf("ReadPackagesFromFile returned error: %v", err)
+	}
+
+	want := []NuGetPackage{
+		{ID: "Newtonsoft.Json", Version: "13.0.3"},
+		{ID: "Internal.Only.Widgets", Version: "1.0.0"},
+	}
+	if len(lookup.Packages) != len(want) {
+		t.Fatalf("got %d packages, want %d: %+v", len(lookup.Packages), len(want), lookup.Packages)
+	}
+	for i, pkg := range want {
+		if lookup.Packages[i] != pkg {
+			t.Errorf("package %d = %+v, want %+v", i, lookup.Packages[i], pkg)
+		}
+	}
+
+	if got := lookup.GetLanguage(); got != "nuget" {
+		t.Errorf("GetLanguage() = %q, want %q", got, "nuget")
+	}
+}
@@ -5,30 +5,82 @@ import (
 	"fmt"
 	"io/ioutil"
 	"net/http"
+	"regexp"
 	"strings"
 	"time"
 
+	"github.com/h0tak88r/confused/internal/cache"
 	"github.com/h0tak88r/confused/internal/types"
+	"github.com/h0tak88r/confused/pkg/registries"
+	"golang.org/x/time/rate"
 )
 
+// defaultPyPIRegistry is pypi.org, used when no --registry/
+// --internal-registry flag overrides the "pip" ecosystem.
+const defaultPyPIRegistry = "https://pypi.org/project"
+
+// pipHashPattern matches pip's requirements.txt hash-checking mode syntax,
+// e.g. "--hash=sha256:abcdef...". A single line can pin more than one hash
+// (`pip-compile --generate-hashes` pins one per published sdist/wheel), so
+// callers must use FindAllStringSubmatch rather than FindStringSubmatch.
+var pipHashPattern = regexp.MustCompile(`--hash=sha256:([a-fA-F0-9]+)`)
+
+// PythonPackage is a single requirement parsed from a requirements.txt line,
+// optionally pinned to one or more sha256 digests via pip's
+// `--hash=sha256:...` hash-checking mode syntax.
+type PythonPackage struct {
+	Name          string
+	Version       string
+	PinnedSha256s []string
+}
+
 // PythonLookup represents a collection of python packages to be tested for dependency confusion.
 type PythonLookup struct {
-	Packages       []string
-	Verbose        bool
-	ctx            context.Context
-	timeout        time.Duration
-	rateLimit      int
-	packageDetails []types.PackageDetail
+	Packages          []PythonPackage
+	Verbose           bool
+	ctx               context.Context
+	timeout           time.Duration
+	rateLimit         int
+	limiter           *rate.Limiter
+	registrySet       *registries.Set
+	workers           int
+	packageDetails    []types.PackageDetail
+	notAvailableCache []string
+	resolved          bool
+	cache             cache.Store
+	cacheTTL          time.Duration
+	httpClient        *http.Client
+	metrics           *Metrics
+}
+
+// SetCache wires store/ttl in so isAvailableInPublic checks are served
+// from cache before falling through to a live registry probe.
+func (p *PythonLookup) SetCache(store cache.Store, ttl time.Duration) {
+	p.cache = store
+	p.cacheTTL = ttl
+}
+
+// SetHTTPClient points this resolver at a shared, instrumented *http.Client
+// (see ResolveAll) instead of building one per registry via Registry.Client.
+func (p *PythonLookup) SetHTTPClient(client *http.Client) {
+	p.httpClient = client
+}
+
+// SetMetrics points this resolver at a shared Metrics instance so its
+// registry checks are counted alongside every other resolver ResolveAll runs.
+func (p *PythonLookup) SetMetrics(m *Metrics) {
+	p.metrics = m
 }
 
 // NewPythonLookup constructs a `PythonLookup` struct and returns it
 func NewPythonLookup(verbose bool) types.PackageResolver {
 	return &PythonLookup{
-		Packages:       []string{},
+		Packages:       []PythonPackage{},
 		Verbose:        verbose,
 		ctx:            context.Background(),
 		timeout:        30 * time.Second,
 		rateLimit:      100,
+		workers:        defaultWorkers,
 		packageDetails: []types.PackageDetail{},
 	}
 }
@@ -50,13 +102,22 @@ func (p *PythonLookup) ReadPackagesFromFile(filename string) error {
 		if len(l) > 0 {
 			// Support line continuation
 			if strings.HasSuffix(l, "\\") {
-				line += l[:len(l) - 1]
+				line += l[:len(l)-1]
 				continue
 			}
 			line += l
 			pkgrow := strings.FieldsFunc(line, p.pipSplit)
 			if len(pkgrow) > 0 {
-				p.Packages = append(p.Packages, strings.TrimSpace(pkgrow[0]))
+				pkg := PythonPackage{Name: strings.TrimSpace(pkgrow[0])}
+				if len(pkgrow) > 1 {
+					pkg.Version = strings.TrimSpace(pkgrow[1])
+				}
+				if matches := pipHashPattern.FindAllStringSubmatch(line, -1); matches != nil {
+					for _, m := range matches {
+						pkg.PinnedSha256s = append(pkg.PinnedSha256s, m[1])
+					}
+				}
+				p.Packages = append(p.Packages, pkg)
 			}
 			// reset the line variable
 			line = ""
@@ -69,15 +130,82 @@ func (p *PythonLookup) ReadPackagesFromFile(filename string) error {
 //
 // Returns a slice of strings with any python packages not in the pypi package repository
 func (p *PythonLookup) PackagesNotInPublic() []string {
-	notavail := []string{}
-	for _, pkg := range p.Packages {
-		if !p.isAvailableInPublic(pkg) {
-			notavail = append(notavail, pkg)
-		}
+	if p.resolved {
+		return p.notAvailableCache
 	}
+	notavail, _ := p.resolve(context.Background())
 	return notavail
 }
 
+// ResolveWithContext behaves like PackagesNotInPublic but aborts as soon as
+// ctx is cancelled and honors the rate limiter set via SetLimiter, so a
+// SIGINT during an org scan stops in-flight registry probes instead of
+// running to completion.
+func (p *PythonLookup) ResolveWithContext(ctx context.Context) error {
+	p.ctx = ctx
+	notavail, err := p.resolve(ctx)
+	if err != nil {
+		return err
+	}
+	p.notAvailableCache = notavail
+	p.resolved = true
+	return nil
+}
+
+// resolve dispatches an isConfused check per package across p.workers
+// goroutines, rate-limited by p.limiter, returning the confused packages in
+// the same order as p.Packages.
+func (p *PythonLookup) resolve(ctx context.Context) ([]string, error) {
+	indices, err := resolveConcurrently(ctx, len(p.Packages), p.workers, func(ctx context.Context, i int) (bool, error) {
+		if err := waitForToken(ctx, p.limiter); err != nil {
+			return false, err
+		}
+		return p.isConfused(p.Packages[i].Name, p.Packages[i].Version), nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	notavail := make([]string, 0, len(indices))
+	for _, i := range indices {
+		notavail = append(notavail, p.Packages[i].Name)
+	}
+	return notavail, nil
+}
+
+// SetLimiter points this resolver at a shared rate limiter.
+func (p *PythonLookup) SetLimiter(limiter *rate.Limiter) {
+	p.limiter = limiter
+}
+
+// SetWorkers points this resolver at the configured --workers concurrency
+// for its registry probes.
+func (p *PythonLookup) SetWorkers(workers int) {
+	if workers > 0 {
+		p.workers = workers
+	}
+}
+
+// SetRegistries points this resolver at the configured public/internal PyPI
+// mirrors, so a package only counts as "public" if it's found in one of the
+// configured public registries (defaulting to pypi.org), and only counts as
+// confused if it's also present in a configured internal one.
+func (p *PythonLookup) SetRegistries(regs *registries.Set) {
+	p.registrySet = regs
+}
+
+// isConfused reports whether pkgname is absent from every configured public
+// PyPI registry and, when an internal allowlist was configured, present (or
+// expected, if no internal allowlist was configured) in one of those.
+func (p *PythonLookup) isConfused(pkgname, version string) bool {
+	if p.isAvailableInPublic(pkgname, version) {
+		return false
+	}
+	if len(p.registrySet.Internal("pip")) == 0 {
+		return true
+	}
+	return p.isAvailableInInternal(pkgname)
+}
+
 func (p *PythonLookup) pipSplit(r rune) bool {
 	delims := []rune{
 		'=',
@@ -92,25 +220,69 @@ func (p *PythonLookup) pipSplit(r rune) bool {
 	return inSlice(r, delims)
 }
 
-// isAvailableInPublic determines if a python package exists in the pypi package repository.
+// inSlice reports whether r is one of the runes in delims.
+func inSlice(r rune, delims []rune) bool {
+	for _, d := range delims {
+		if r == d {
+			return true
+		}
+	}
+	return false
+}
+
+// isAvailableInPublic determines if a python package exists in any of the
+// configured public PyPI registries (pypi.org by default). version is part
+// of the cache key (not just pkgname) since a requirements.txt can pin the
+// same package to different versions across repos, and a future checkRegistry
+// that also verifies the pinned version was published must not share a
+// cached answer with a different version of the same package.
 //
-// Returns true if the package exists in the pypi package repository.
-func (p *PythonLookup) isAvailableInPublic(pkgname string) bool {
-	if p.Verbose {
-		fmt.Print("Checking: https://pypi.org/project/" + pkgname + "/ : ")
+// Returns true if the package exists in a public PyPI registry.
+func (p *PythonLookup) isAvailableInPublic(pkgname, version string) bool {
+	if available, ok := cachedAvailability(p.cache, "pip", pkgname, version); ok {
+		p.metrics.RecordCacheHit()
+		return available
+	}
+	available := false
+	for _, reg := range p.registrySet.Public("pip", defaultPyPIRegistry) {
+		if p.checkRegistry(reg, pkgname) {
+			available = true
+			break
+		}
 	}
-	resp, err := http.Get("https://pypi.org/project/" + pkgname + "/")
+	setCachedAvailability(p.cache, p.cacheTTL, "pip", pkgname, version, available)
+	return available
+}
+
+// isAvailableInInternal determines if a python package exists in any of the
+// registries configured as "internal" via --internal-registry.
+func (p *PythonLookup) isAvailableInInternal(pkgname string) bool {
+	for _, reg := range p.registrySet.Internal("pip") {
+		if p.checkRegistry(reg, pkgname) {
+			return true
+		}
+	}
+	return false
+}
+
+// checkRegistry determines if a python package exists in a single registry,
+// via PyPI's JSON API (https://pypi.org/pypi/<pkg>/json), which 404s for
+// non-existent packages rather than requiring an HTML page to be scraped.
+// 429/5xx are retried with backoff centrally by doRegistryGET.
+func (p *PythonLookup) checkRegistry(reg registries.Registry, pkgname string) bool {
+	url := strings.TrimSuffix(reg.URL, "/project") + "/pypi/" + pkgname + "/json"
+	resp, _, _, err := doRegistryGET(reg, url, p.timeout, p.httpClient, p.metrics)
 	if err != nil {
-		fmt.Printf(" [W] Error when trying to request https://pypi.org/project/"+pkgname+"/ : %s\n", err)
+		if p.Verbose {
+			fmt.Printf(" [W] Error when trying to request "+url+" : %s\n", err)
+		}
 		return false
 	}
+	defer resp.Body.Close()
 	if p.Verbose {
-		fmt.Printf("%s\n", resp.Status)
-	}
-	if resp.StatusCode == http.StatusOK {
-		return true
+		fmt.Printf("Checking: %s : %s\n", url, resp.Status)
 	}
-	return false
+	return resp.StatusCode == http.StatusOK
 }
 
 // GetPackageCount returns the number of packages
@@ -149,23 +321,87 @@ func (p *PythonLookup) GetPackageDetails() []types.PackageDetail {
 // buildPackageDetails builds detailed package information
 func (p *PythonLookup) buildPackageDetails() {
 	p.packageDetails = []types.PackageDetail{}
-	
-	for _, pkgName := range p.Packages {
+
+	for _, pkg := range p.Packages {
 		detail := types.PackageDetail{
-			Name:    pkgName,
-			Version: "",
+			Name:    pkg.Name,
+			Version: pkg.Version,
 			Type:    "dependency",
 			Metadata: map[string]interface{}{
-				"original_name": pkgName,
+				"original_name": pkg.Name,
 			},
 		}
-		
+
 		// Check if package is vulnerable
-		if !p.isAvailableInPublic(pkgName) {
+		if p.isConfused(pkg.Name, pkg.Version) {
 			detail.Vulnerable = true
 			detail.Reason = "Package not available in public PyPI registry"
 		}
-		
+
+		for _, reg := range p.registrySet.Public("pip", defaultPyPIRegistry) {
+			if matched, ok, versions := CheckVersionAgainstFetch(pkg.Version, func() ([]string, error) {
+				return fetchPyPIVersions(reg, pkg.Name)
+			}); ok {
+				detail.Metadata["version_matched"] = matched
+				detail.Metadata["available_versions"] = versions
+			}
+			if info, err := fetchPyPIPackageInfo(reg, pkg.Name, pkg.Version); err == nil {
+				if detail.Version == "" {
+					detail.Version = info.Version
+				}
+				detail.Metadata["author"] = info.Author
+				detail.Metadata["upload_time"] = info.UploadTime
+				detail.Metadata["yanked"] = info.Yanked
+			}
+			break
+		}
+
+		// If the requirements.txt line pinned sha256(es) via --hash, compare
+		// them against what PyPI currently publishes for that exact version.
+		// No overlap between the pinned and published sets means every
+		// artifact behind that version was swapped after the pin was
+		// committed - a supply-chain signal the namespace-presence check
+		// above can't see.
+		if len(pkg.PinnedSha256s) > 0 && pkg.Version != "" && !detail.Vulnerable {
+			p.checkIntegrity(pkg, &detail)
+		}
+
 		p.packageDetails = append(p.packageDetails, detail)
 	}
-}
\ No newline at end of file
+}
+
+// checkIntegrity compares pkg's requirements.txt-pinned sha256 set against
+// the digests PyPI currently publishes for that version's release files
+// (sdist and every wheel), flagging integrity drift only if none of the
+// pinned hashes match any published digest. A `pip-compile --generate-hashes`
+// line routinely pins one hash per published file, so comparing single
+// arbitrary values from each side would false-positive on ordinary,
+// unmodified packages.
+func (p *PythonLookup) checkIntegrity(pkg PythonPackage, detail *types.PackageDetail) {
+	for _, reg := range p.registrySet.Public("pip", defaultPyPIRegistry) {
+		published, err := fetchPyPISha256(reg, pkg.Name, pkg.Version)
+		if err != nil || len(published) == 0 {
+			continue
+		}
+		detail.Metadata["pinned_sha256"] = pkg.PinnedSha256s
+		detail.Metadata["published_sha256"] = published
+		if !anyHashMatches(pkg.PinnedSha256s, published) {
+			detail.Vulnerable = true
+			detail.Reason = "integrity drift"
+		}
+		return
+	}
+}
+
+// anyHashMatches reports whether any hash in pinned case-insensitively
+// matches any hash in published.
+func anyHashMatches(pinned, published []string) bool {
+	for _, p := range pinned {
+		for _, pub := range published {
+			if strings.EqualFold(p, pub) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,22 @@
+package resolvers
+
+import (
+	"context"
+
+	"golang.org/x/time/rate"
+)
+
+// ContextAware is implemented by resolvers that can be pointed at a shared,
+// per-host rate limiter so registry probes respect a global QPS ceiling.
+type ContextAware interface {
+	SetLimiter(*rate.Limiter)
+}
+
+// waitForToken blocks until limiter permits another request, or returns
+// ctx.Err() if ctx is cancelled first. A nil limiter means unlimited.
+func waitForToken(ctx context.Context, limiter *rate.Limiter) error {
+	if limiter == nil {
+		return nil
+	}
+	return limiter.Wait(ctx)
+}
@@ -0,0 +1,94 @@
+package resolvers
+
+import (
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/h0tak88r/confused/pkg/registries"
+)
+
+// HTTPClientAware is implemented by resolvers that can be pointed at a
+// shared *http.Client instead of building their own per registries.Registry,
+// so ResolveAll can give every resolver it fans out across the same
+// connection-pooled, instrumented client.
+type HTTPClientAware interface {
+	SetHTTPClient(*http.Client)
+}
+
+// maxRegistryRetries bounds how many times doRegistryGET will retry a single
+// request after a 429/5xx, matching the limit every resolver's checkRegistry
+// used to enforce individually.
+const maxRegistryRetries = 3
+
+// initialRegistryBackoff is the delay before the first retry (absent a
+// Retry-After header); each subsequent retry doubles it, plus up to 20%
+// jitter so a burst of resolvers hitting the same registry don't all retry
+// in lockstep.
+const initialRegistryBackoff = 10 * time.Second
+
+// doRegistryGET issues a GET against url against reg (applying its auth
+// header), centralizing the 429/5xx throttle/retry/backoff logic that used
+// to be duplicated in every resolver's checkRegistry. client, when non-nil,
+// overrides reg.Client(timeout) - this is how ResolveAll points every
+// resolver at one shared, instrumented http.Client instead of each building
+// its own. metrics, when non-nil, records one RecordRequest per attempt. The
+// caller is responsible for closing the returned response's body. retries
+// reports how many retries were consumed; retries == maxRegistryRetries with
+// a non-nil resp still carrying a 429/5xx status means the retry budget was
+// exhausted.
+func doRegistryGET(reg registries.Registry, url string, timeout time.Duration, client *http.Client, metrics *Metrics) (resp *http.Response, duration time.Duration, retries int, err error) {
+	if client == nil {
+		client = reg.Client(timeout)
+	}
+
+	start := time.Now()
+	backoff := initialRegistryBackoff
+	for {
+		req, reqErr := http.NewRequest("GET", url, nil)
+		if reqErr != nil {
+			return nil, time.Since(start), retries, reqErr
+		}
+		reg.ApplyAuth(req)
+		attemptStart := time.Now()
+		resp, err = client.Do(req)
+		metrics.RecordRequest(time.Since(attemptStart))
+		if err != nil {
+			return nil, time.Since(start), retries, err
+		}
+		if (resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= http.StatusInternalServerError) && retries < maxRegistryRetries {
+			wait := retryAfter(resp.Header.Get("Retry-After"))
+			if wait <= 0 {
+				wait = jitter(backoff)
+			}
+			resp.Body.Close()
+			retries++
+			time.Sleep(wait)
+			backoff *= 2
+			continue
+		}
+		return resp, time.Since(start), retries, nil
+	}
+}
+
+// retryAfter parses a Retry-After response header (seconds, per RFC 7231;
+// HTTP-date is not expected from the registries confused talks to) into a
+// duration, returning 0 if it's absent or unparseable so the caller falls
+// back to its own backoff.
+func retryAfter(header string) time.Duration {
+	if header == "" {
+		return 0
+	}
+	seconds, err := strconv.Atoi(header)
+	if err != nil || seconds < 0 {
+		return 0
+	}
+	return time.Duration(seconds) * time.Second
+}
+
+// jitter returns d plus up to 20% extra, so concurrent resolvers backing off
+// from the same registry don't all retry at exactly the same instant.
+func jitter(d time.Duration) time.Duration {
+	return d + time.Duration(rand.Int63n(int64(d)/5+1))
+}
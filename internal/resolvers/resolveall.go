@@ -0,0 +1,112 @@
+package resolvers
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/h0tak88r/confused/internal/types"
+)
+
+// resolveAllWorkers bounds how many resolvers ResolveAll runs concurrently.
+// Each resolver already fans its own package lookups out across --workers
+// (see WorkersAware), so this caps ecosystems-in-flight rather than
+// packages-in-flight; a monorepo with five ecosystems doesn't need more than
+// this to keep every registry busy.
+const resolveAllWorkers = 5
+
+// LanguageResult is one resolver's outcome from a ResolveAll run.
+type LanguageResult struct {
+	Language string
+	Confused []string
+	Total    int
+	Duration time.Duration
+	Err      error
+}
+
+// Results aggregates every resolver ResolveAll ran, keyed by GetLanguage(),
+// plus the request/latency/cache-hit counters they shared.
+type Results struct {
+	Languages map[string]LanguageResult
+	Metrics   MetricsSnapshot
+}
+
+// ResolveAll loads pkgs into resolvers (pkgs maps each resolver's
+// GetLanguage() to the manifest paths DetectLanguages/a caller found for it,
+// via ReadPackagesFromFile) and resolves them concurrently across up to
+// resolveAllWorkers goroutines, honoring ctx cancellation. Every resolver
+// that implements HTTPClientAware/MetricsAware is pointed at one shared,
+// connection-pooled *http.Client and one shared Metrics instance, so
+// request count, latency, and cache hits are reported across the whole run
+// instead of per resolver, and registry probes reuse connections instead of
+// each opening their own. Per-resolver rate limiting (ContextAware) and
+// registry mirrors (registries.Aware) are expected to already be wired via
+// GetResolverForLanguageWithContext/WithCache before resolvers reaches here -
+// see runMonorepoCommand in cmd/confused, the current caller.
+func ResolveAll(ctx context.Context, resolvers []types.PackageResolver, pkgs map[string][]string) (Results, error) {
+	client := &http.Client{Transport: http.DefaultTransport}
+	metrics := &Metrics{}
+
+	for _, resolver := range resolvers {
+		for _, manifest := range pkgs[resolver.GetLanguage()] {
+			if err := resolver.ReadPackagesFromFile(manifest); err != nil {
+				return Results{}, err
+			}
+		}
+		if clientAware, ok := resolver.(HTTPClientAware); ok {
+			clientAware.SetHTTPClient(client)
+		}
+		if metricsAware, ok := resolver.(MetricsAware); ok {
+			metricsAware.SetMetrics(metrics)
+		}
+	}
+
+	results := make([]LanguageResult, len(resolvers))
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+
+	workers := resolveAllWorkers
+	if workers > len(resolvers) {
+		workers = len(resolvers)
+	}
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				resolver := resolvers[i]
+				start := time.Now()
+				err := resolver.ResolveWithContext(ctx)
+				results[i] = LanguageResult{
+					Language: resolver.GetLanguage(),
+					Confused: resolver.PackagesNotInPublic(),
+					Total:    resolver.GetPackageCount(),
+					Duration: time.Since(start),
+					Err:      err,
+				}
+			}
+		}()
+	}
+
+dispatch:
+	for i := range resolvers {
+		select {
+		case jobs <- i:
+		case <-ctx.Done():
+			break dispatch
+		}
+	}
+	close(jobs)
+	wg.Wait()
+
+	if err := ctx.Err(); err != nil {
+		return Results{}, err
+	}
+
+	languages := make(map[string]LanguageResult, len(results))
+	for _, r := range results {
+		languages[r.Language] = r
+	}
+	return Results{Languages: languages, Metrics: metrics.Snapshot()}, nil
+}
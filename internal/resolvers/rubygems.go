@@ -0,0 +1,397 @@
+package resolvers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/h0tak88r/confused/internal/cache"
+	"github.com/h0tak88r/confused/internal/types"
+	"github.com/h0tak88r/confused/pkg/logger"
+	"github.com/h0tak88r/confused/pkg/registries"
+	"golang.org/x/time/rate"
+)
+
+// defaultRubyGemsRegistry is rubygems.org, used when no --registry/
+// --internal-registry flag overrides the "rubygems" ecosystem.
+const defaultRubyGemsRegistry = "https://rubygems.org/api/v1/gems"
+
+// gemLine matches a single `gem "name"` or `gem "name", "version"` Gemfile
+// declaration, capturing the name and, when present, the first version
+// constraint argument.
+var gemLine = regexp.MustCompile(`^\s*gem\s+['"]([^'"]+)['"](?:\s*,\s*['"]([^'"]+)['"])?`)
+
+// gemspecLine matches a `name.version = "1.2.3"` line from a Gemfile.lock's
+// GEM section.
+var gemspecLine = regexp.MustCompile(`^\s{4}([A-Za-z0-9_.-]+)\s+\(([^)]+)\)`)
+
+// RubyGemsResponse mirrors the bits of a rubygems.org gem info document
+// needed to recognize whether a name is actually published.
+type RubyGemsResponse struct {
+	Name  string `json:"name"`
+	Error string `json:"error"`
+}
+
+// NotAvailable reports whether this response indicates the gem isn't
+// actually published, despite a 200 status.
+func (r RubyGemsResponse) NotAvailable() bool {
+	return r.Error != ""
+}
+
+// RubyGemsPackage is a single dependency parsed from a Gemfile or
+// Gemfile.lock.
+type RubyGemsPackage struct {
+	Name    string
+	Version string
+}
+
+// RubyGemsLookup represents a collection of rubygems packages to be tested for dependency confusion.
+type RubyGemsLookup struct {
+	Packages          []RubyGemsPackage
+	Verbose           bool
+	ctx               context.Context
+	timeout           time.Duration
+	rateLimit         int
+	limiter           *rate.Limiter
+	registrySet       *registries.Set
+	log               *logger.Logger
+	workers           int
+	packageDetails    []types.PackageDetail
+	notAvailableCache []string
+	resolved          bool
+	cache             cache.Store
+	cacheTTL          time.Duration
+	httpClient        *http.Client
+	metrics           *Metrics
+}
+
+// SetCache wires store/ttl in so isAvailableInPublic checks are served
+// from cache before falling through to a live registry probe.
+func (r *RubyGemsLookup) SetCache(store cache.Store, ttl time.Duration) {
+	r.cache = store
+	r.cacheTTL = ttl
+}
+
+// SetHTTPClient points this resolver at a shared, instrumented *http.Client
+// (see ResolveAll) instead of building one per registry via Registry.Client.
+func (r *RubyGemsLookup) SetHTTPClient(client *http.Client) {
+	r.httpClient = client
+}
+
+// SetMetrics points this resolver at a shared Metrics instance so its
+// registry checks are counted alongside every other resolver ResolveAll runs.
+func (r *RubyGemsLookup) SetMetrics(m *Metrics) {
+	r.metrics = m
+}
+
+// NewRubyGemsLookup constructs an `RubyGemsLookup` struct and returns it.
+func NewRubyGemsLookup(verbose bool) types.PackageResolver {
+	return &RubyGemsLookup{
+		Packages:       []RubyGemsPackage{},
+		Verbose:        verbose,
+		ctx:            context.Background(),
+		timeout:        30 * time.Second,
+		rateLimit:      100,
+		workers:        defaultWorkers,
+		packageDetails: []types.PackageDetail{},
+	}
+}
+
+// ReadPackagesFromFile reads package information from a Gemfile or
+// Gemfile.lock.
+//
+// Returns any errors encountered
+func (r *RubyGemsLookup) ReadPackagesFromFile(filename string) error {
+	rawfile, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return err
+	}
+
+	for _, line := range strings.Split(string(rawfile), "\n") {
+		if m := gemLine.FindStringSubmatch(line); m != nil {
+			r.Packages = append(r.Packages, RubyGemsPackage{Name: m[1], Version: m[2]})
+			continue
+		}
+		if m := gemspecLine.FindStringSubmatch(line); m != nil {
+			r.Packages = append(r.Packages, RubyGemsPackage{Name: m[1], Version: m[2]})
+		}
+	}
+	return nil
+}
+
+// PackagesNotInPublic determines if a rubygems package does not exist in the public rubygems package repository.
+//
+// Returns a slice of strings with any rubygems packages not in the public rubygems package repository
+func (r *RubyGemsLookup) PackagesNotInPublic() []string {
+	if r.resolved {
+		return r.notAvailableCache
+	}
+	notavail, _ := r.resolve(context.Background())
+	return notavail
+}
+
+// ResolveWithContext behaves like PackagesNotInPublic but aborts as soon as
+// ctx is cancelled and honors the rate limiter set via SetLimiter, so a
+// SIGINT during an org scan stops in-flight registry probes instead of
+// running to completion.
+func (r *RubyGemsLookup) ResolveWithContext(ctx context.Context) error {
+	r.ctx = ctx
+	notavail, err := r.resolve(ctx)
+	if err != nil {
+		return err
+	}
+	r.notAvailableCache = notavail
+	r.resolved = true
+	return nil
+}
+
+// resolve dispatches an isConfused check per package across r.workers
+// goroutines, rate-limited by r.limiter, returning the confused packages in
+// the same order as r.Packages.
+func (r *RubyGemsLookup) resolve(ctx context.Context) ([]string, error) {
+	indices, err := resolveConcurrently(ctx, len(r.Packages), r.workers, func(ctx context.Context, i int) (bool, error) {
+		if err := waitForToken(ctx, r.limiter); err != nil {
+			return false, err
+		}
+		return r.isConfused(r.Packages[i]), nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	notavail := make([]string, 0, len(indices))
+	for _, i := range indices {
+		notavail = append(notavail, r.Packages[i].Name)
+	}
+	return notavail, nil
+}
+
+// SetLimiter points this resolver at a shared rate limiter.
+func (r *RubyGemsLookup) SetLimiter(limiter *rate.Limiter) {
+	r.limiter = limiter
+}
+
+// SetWorkers points this resolver at the configured --workers concurrency
+// for its registry probes.
+func (r *RubyGemsLookup) SetWorkers(workers int) {
+	if workers > 0 {
+		r.workers = workers
+	}
+}
+
+// SetRegistries points this resolver at the configured public/internal
+// rubygems mirrors, so a package only counts as "public" if it's found in
+// one of the configured public registries (defaulting to rubygems.org), and
+// only counts as confused if it's also present in a configured internal one.
+func (r *RubyGemsLookup) SetRegistries(regs *registries.Set) {
+	r.registrySet = regs
+}
+
+// SetLogger points this resolver at a structured logger, so each registry
+// HTTP check emits one event record instead of an ad-hoc fmt.Printf line.
+func (r *RubyGemsLookup) SetLogger(log *logger.Logger) {
+	r.log = log
+}
+
+// logCheck emits a structured "registry_check" event for a single HTTP
+// check. If no logger was wired via SetLogger, it falls back to printing a
+// human-readable line so the resolver still works when used standalone.
+func (r *RubyGemsLookup) logCheck(reg registries.Registry, pkg RubyGemsPackage, retry int, statusCode int, duration time.Duration, result string) {
+	if r.log == nil {
+		fmt.Printf(" [%s] %s (registry=%s status=%d retry=%d)\n", result, pkg.Name, reg.URL, statusCode, retry)
+		return
+	}
+	r.log.WithFields(map[string]interface{}{
+		"event":       "registry_check",
+		"registry":    reg.URL,
+		"package":     pkg.Name,
+		"version":     pkg.Version,
+		"status_code": statusCode,
+		"retry":       retry,
+		"duration_ms": duration.Milliseconds(),
+		"result":      result,
+	}).Debug("rubygems registry check")
+}
+
+// isConfused reports whether pkg is absent from every configured public
+// rubygems registry and, when an internal allowlist was configured, present
+// (or expected, if no internal allowlist was configured) in one of those.
+func (r *RubyGemsLookup) isConfused(pkg RubyGemsPackage) bool {
+	if r.isAvailableInPublic(pkg) {
+		return false
+	}
+	if len(r.registrySet.Internal("rubygems")) == 0 {
+		return true
+	}
+	return r.isAvailableInInternal(pkg)
+}
+
+// isAvailableInPublic determines if a rubygems package exists in any of the
+// configured public rubygems registries (rubygems.org by default).
+//
+// Returns true if the package exists in a public rubygems registry.
+func (r *RubyGemsLookup) isAvailableInPublic(pkg RubyGemsPackage) bool {
+	if available, ok := cachedAvailability(r.cache, "rubygems", pkg.Name, pkg.Version); ok {
+		r.metrics.RecordCacheHit()
+		return available
+	}
+	available := false
+	for _, reg := range r.registrySet.Public("rubygems", defaultRubyGemsRegistry) {
+		if r.checkRegistry(reg, pkg) {
+			available = true
+			break
+		}
+	}
+	setCachedAvailability(r.cache, r.cacheTTL, "rubygems", pkg.Name, pkg.Version, available)
+	return available
+}
+
+// isAvailableInInternal determines if a rubygems package exists in any of
+// the registries configured as "internal" via --internal-registry.
+func (r *RubyGemsLookup) isAvailableInInternal(pkg RubyGemsPackage) bool {
+	for _, reg := range r.registrySet.Internal("rubygems") {
+		if r.checkRegistry(reg, pkg) {
+			return true
+		}
+	}
+	return false
+}
+
+// checkRegistry determines if a rubygems package exists in a single
+// registry. 429/5xx are retried with backoff centrally by doRegistryGET.
+func (r *RubyGemsLookup) checkRegistry(reg registries.Registry, pkg RubyGemsPackage) bool {
+	url := reg.URL + "/" + pkg.Name + ".json"
+	resp, duration, retries, err := doRegistryGET(reg, url, r.timeout, r.httpClient, r.metrics)
+	if err != nil {
+		r.logCheck(reg, pkg, retries, 0, duration, fmt.Sprintf("request_error: %s", err))
+		return false
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		r.logCheck(reg, pkg, retries, resp.StatusCode, duration, "not_available")
+		return false
+	}
+	body, _ := ioutil.ReadAll(resp.Body)
+	gemResp := RubyGemsResponse{}
+	_ = json.Unmarshal(body, &gemResp)
+	if gemResp.NotAvailable() {
+		r.logCheck(reg, pkg, retries, resp.StatusCode, duration, "unpublished")
+		return false
+	}
+	if matched, ok, _ := CheckVersionAgainstFetch(pkg.Version, func() ([]string, error) {
+		return fetchRubyGemsVersions(reg, pkg.Name)
+	}); ok && !matched {
+		r.logCheck(reg, pkg, retries, resp.StatusCode, duration, "version_not_published")
+		return false
+	}
+	r.logCheck(reg, pkg, retries, resp.StatusCode, duration, "available")
+	return true
+}
+
+// GetPackageCount returns the number of packages
+func (r *RubyGemsLookup) GetPackageCount() int {
+	return len(r.Packages)
+}
+
+// GetLanguage returns the language name
+func (r *RubyGemsLookup) GetLanguage() string {
+	return "rubygems"
+}
+
+// SetContext sets the context for the resolver
+func (r *RubyGemsLookup) SetContext(ctx context.Context) {
+	r.ctx = ctx
+}
+
+// SetTimeout sets the timeout for requests
+func (r *RubyGemsLookup) SetTimeout(timeout time.Duration) {
+	r.timeout = timeout
+}
+
+// SetRateLimit sets the rate limit for requests
+func (r *RubyGemsLookup) SetRateLimit(rate int) {
+	r.rateLimit = rate
+}
+
+// GetPackageDetails returns detailed information about packages
+func (r *RubyGemsLookup) GetPackageDetails() []types.PackageDetail {
+	if len(r.packageDetails) == 0 {
+		r.buildPackageDetails()
+	}
+	return r.packageDetails
+}
+
+// buildPackageDetails builds detailed package information
+func (r *RubyGemsLookup) buildPackageDetails() {
+	r.packageDetails = []types.PackageDetail{}
+
+	for _, pkg := range r.Packages {
+		detail := types.PackageDetail{
+			Name:    pkg.Name,
+			Version: pkg.Version,
+			Type:    "dependency",
+			Metadata: map[string]interface{}{
+				"original_name": pkg.Name,
+			},
+		}
+
+		if r.isConfused(pkg) {
+			detail.Vulnerable = true
+			detail.Reason = "Package not available in public rubygems registry"
+		}
+
+		for _, reg := range r.registrySet.Public("rubygems", defaultRubyGemsRegistry) {
+			if matched, ok, versions := CheckVersionAgainstFetch(pkg.Version, func() ([]string, error) {
+				return fetchRubyGemsVersions(reg, pkg.Name)
+			}); ok {
+				detail.Metadata["version_matched"] = matched
+				detail.Metadata["available_versions"] = versions
+			}
+			break
+		}
+
+		r.packageDetails = append(r.packageDetails, detail)
+	}
+}
+
+// rubyGemsVersionsResponse is a single element of the JSON array returned by
+// rubygems.org's /api/v1/versions/{gem}.json endpoint.
+type rubyGemsVersionsResponse struct {
+	Number string `json:"number"`
+}
+
+// fetchRubyGemsVersions fetches the published versions for gemname from
+// reg's versions endpoint.
+func fetchRubyGemsVersions(reg registries.Registry, gemname string) ([]string, error) {
+	url := strings.TrimSuffix(reg.URL, "/gems") + "/versions/" + gemname + ".json"
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+	reg.ApplyAuth(req)
+	resp, err := reg.Client(0).Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("rubygems registry returned %s for %s", resp.Status, url)
+	}
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	var versions []rubyGemsVersionsResponse
+	if err := json.Unmarshal(body, &versions); err != nil {
+		return nil, err
+	}
+	out := make([]string, 0, len(versions))
+	for _, v := range versions {
+		out = append(out, v.Number)
+	}
+	return out, nil
+}
@@ -0,0 +1,463 @@
+package resolvers
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/h0tak88r/confused/pkg/registries"
+)
+
+// VersionConstraint is a parsed dependency version requirement, e.g.
+// "^1.2.3", "~>2.0", a Maven "[1.0,2.0)" range, or a bare version.
+type VersionConstraint struct {
+	Operator string // ==, >=, <=, >, <, ^, ~, ~>, or "" for an exact/bare version
+	Version  string
+	Max      string // set for Maven-style bracket ranges
+	MaxIncl  bool
+}
+
+var versionConstraintPattern = regexp.MustCompile(`^(==|>=|<=|>|<|\^|~>|~)?\s*v?([0-9][0-9A-Za-z.\-+]*)$`)
+
+// ParseVersionConstraint parses a raw dependency version string into a
+// constraint. It returns ok=false for anything it can't confidently parse
+// (git refs, "*", "dev-master", path/URL references, ...), so callers can
+// fall back to a namespace-only check instead of silently mis-evaluating it.
+func ParseVersionConstraint(raw string) (constraint VersionConstraint, ok bool) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" || raw == "*" {
+		return VersionConstraint{}, false
+	}
+	if strings.HasPrefix(raw, "[") || strings.HasPrefix(raw, "(") {
+		return parseMavenRange(raw)
+	}
+	if m := versionConstraintPattern.FindStringSubmatch(raw); m != nil {
+		return VersionConstraint{Operator: m[1], Version: canonicalizeVersion(m[2])}, true
+	}
+	return VersionConstraint{}, false
+}
+
+// parseMavenRange parses Maven's "[1.0,2.0)" / "[1.0]" interval syntax.
+func parseMavenRange(raw string) (VersionConstraint, bool) {
+	inclusiveMin := strings.HasPrefix(raw, "[")
+	inclusiveMax := strings.HasSuffix(raw, "]")
+	trimmed := strings.TrimRight(strings.TrimLeft(raw, "[("), "])")
+	parts := strings.SplitN(trimmed, ",", 2)
+	if len(parts) == 1 {
+		return VersionConstraint{Operator: "==", Version: canonicalizeVersion(parts[0])}, true
+	}
+	min := strings.TrimSpace(parts[0])
+	max := strings.TrimSpace(parts[1])
+	c := VersionConstraint{MaxIncl: inclusiveMax}
+	if max != "" {
+		c.Max = canonicalizeVersion(max)
+	}
+	if min != "" {
+		c.Version = canonicalizeVersion(min)
+		if inclusiveMin {
+			c.Operator = ">="
+		} else {
+			c.Operator = ">"
+		}
+	}
+	return c, true
+}
+
+// canonicalizeVersion strips a leading "v" and normalizes pre-release
+// separators so e.g. "1.0.0-RC1" and "1.0.0_rc1" compare the same way.
+func canonicalizeVersion(v string) string {
+	v = strings.TrimPrefix(strings.TrimSpace(v), "v")
+	return strings.ReplaceAll(v, "_", "-")
+}
+
+// Satisfies reports whether version meets the constraint.
+func (c VersionConstraint) Satisfies(version string) bool {
+	version = canonicalizeVersion(version)
+	if c.Max != "" {
+		cmpMax := compareVersions(version, c.Max)
+		if c.MaxIncl {
+			if cmpMax > 0 {
+				return false
+			}
+		} else if cmpMax >= 0 {
+			return false
+		}
+	}
+	if c.Version == "" {
+		return true
+	}
+	cmp := compareVersions(version, c.Version)
+	switch c.Operator {
+	case ">=":
+		return cmp >= 0
+	case ">":
+		return cmp > 0
+	case "<=":
+		return cmp <= 0
+	case "<":
+		return cmp < 0
+	case "^":
+		return cmp >= 0 && sameMajor(version, c.Version)
+	case "~", "~>":
+		return cmp >= 0 && sameMinor(version, c.Version)
+	default: // "==" or bare version
+		return cmp == 0
+	}
+}
+
+// splitVersion splits a version into its numeric core components and an
+// optional pre-release/build suffix.
+func splitVersion(v string) (core []int, pre string) {
+	main := v
+	if idx := strings.IndexAny(v, "-+"); idx >= 0 {
+		main = v[:idx]
+		pre = v[idx+1:]
+	}
+	for _, part := range strings.Split(main, ".") {
+		n, err := strconv.Atoi(part)
+		if err != nil {
+			n = 0
+		}
+		core = append(core, n)
+	}
+	return core, pre
+}
+
+// compareVersions returns -1, 0, or 1 as a is less than, equal to, or
+// greater than b. A release version is considered greater than any of its
+// own pre-release versions (1.0.0 > 1.0.0-rc1).
+func compareVersions(a, b string) int {
+	coreA, preA := splitVersion(a)
+	coreB, preB := splitVersion(b)
+	for i := 0; i < len(coreA) || i < len(coreB); i++ {
+		var na, nb int
+		if i < len(coreA) {
+			na = coreA[i]
+		}
+		if i < len(coreB) {
+			nb = coreB[i]
+		}
+		if na != nb {
+			if na < nb {
+				return -1
+			}
+			return 1
+		}
+	}
+	switch {
+	case preA == "" && preB == "":
+		return 0
+	case preA == "":
+		return 1
+	case preB == "":
+		return -1
+	default:
+		return strings.Compare(preA, preB)
+	}
+}
+
+func sameMajor(a, b string) bool {
+	ca, _ := splitVersion(a)
+	cb, _ := splitVersion(b)
+	return len(ca) > 0 && len(cb) > 0 && ca[0] == cb[0]
+}
+
+func sameMinor(a, b string) bool {
+	ca, _ := splitVersion(a)
+	cb, _ := splitVersion(b)
+	if len(ca) < 2 || len(cb) < 2 {
+		return sameMajor(a, b)
+	}
+	return ca[0] == cb[0] && ca[1] == cb[1]
+}
+
+// CheckVersionAgainstFetch parses rawConstraint and, if parseable, fetches
+// the registry's published version list via fetch and reports whether any
+// of them satisfies it. ok is false (and fetch is never called) when the
+// constraint can't be parsed, or when fetch itself fails, so callers fall
+// back to treating the package as namespace-only available instead of
+// penalizing it for a version-metadata fetch we couldn't complete.
+func CheckVersionAgainstFetch(rawConstraint string, fetch func() ([]string, error)) (matched, ok bool, availableVersions []string) {
+	constraint, parsed := ParseVersionConstraint(rawConstraint)
+	if !parsed {
+		return false, false, nil
+	}
+	versions, err := fetch()
+	if err != nil || len(versions) == 0 {
+		return false, false, nil
+	}
+	for _, v := range versions {
+		if constraint.Satisfies(v) {
+			return true, true, versions
+		}
+	}
+	return false, true, versions
+}
+
+// mavenMetadata mirrors the bits of a maven-metadata.xml document needed to
+// list a groupId/artifactId's published versions.
+type mavenMetadata struct {
+	Versioning struct {
+		Versions struct {
+			Version []string `xml:"version"`
+		} `xml:"versions"`
+	} `xml:"versioning"`
+}
+
+// fetchMavenVersions fetches the published versions for a Maven
+// groupId/artifactId from reg's maven-metadata.xml.
+func fetchMavenVersions(reg registries.Registry, group, artifact string) ([]string, error) {
+	groupPath := strings.ReplaceAll(group, ".", "/")
+	url := reg.URL + "/" + groupPath + "/" + artifact + "/maven-metadata.xml"
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+	reg.ApplyAuth(req)
+	resp, err := reg.Client(0).Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("maven-metadata.xml not found at %s (status %s)", url, resp.Status)
+	}
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	var meta mavenMetadata
+	if err := xml.Unmarshal(body, &meta); err != nil {
+		return nil, err
+	}
+	return meta.Versioning.Versions.Version, nil
+}
+
+// packagistP2Response mirrors the bits of Packagist's p2 metadata format
+// (https://packagist.org/apidoc#get-package-data) needed to list a package's
+// published versions and, per release, the dist archive's checksum.
+type packagistP2Response struct {
+	Packages map[string][]struct {
+		Version string `json:"version"`
+		Dist    struct {
+			Shasum string `json:"shasum"`
+		} `json:"dist"`
+	} `json:"packages"`
+}
+
+// fetchComposerVersions fetches the published versions for pkgname from
+// reg's p2 metadata endpoint.
+func fetchComposerVersions(reg registries.Registry, pkgname string) ([]string, error) {
+	url := strings.TrimSuffix(reg.URL, "/packages") + "/p2/" + pkgname + ".json"
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+	reg.ApplyAuth(req)
+	resp, err := reg.Client(0).Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("p2 metadata not found at %s (status %s)", url, resp.Status)
+	}
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	var data packagistP2Response
+	if err := json.Unmarshal(body, &data); err != nil {
+		return nil, err
+	}
+	var versions []string
+	for _, pv := range data.Packages[pkgname] {
+		versions = append(versions, pv.Version)
+	}
+	return versions, nil
+}
+
+// fetchComposerDistShasum fetches the dist archive shasum Packagist publishes
+// for pkgname at the given version, for comparison against a composer.lock
+// pin. Returns an empty string with no error if the version has no
+// published dist shasum (e.g. a source-only release).
+func fetchComposerDistShasum(reg registries.Registry, pkgname string, version string) (string, error) {
+	url := strings.TrimSuffix(reg.URL, "/packages") + "/p2/" + pkgname + ".json"
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return "", err
+	}
+	reg.ApplyAuth(req)
+	resp, err := reg.Client(0).Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("p2 metadata not found at %s (status %s)", url, resp.Status)
+	}
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return "", err
+	}
+	var data packagistP2Response
+	if err := json.Unmarshal(body, &data); err != nil {
+		return "", err
+	}
+	for _, pv := range data.Packages[pkgname] {
+		if pv.Version == version {
+			return pv.Dist.Shasum, nil
+		}
+	}
+	return "", nil
+}
+
+// pypiJSONResponse mirrors the bits of PyPI's JSON API
+// (https://pypi.org/pypi/<pkg>/json) needed to list a package's published
+// versions and, per release file, its sha256 digest, upload time, and
+// yanked status, plus the project-level info PyPI reports for its latest
+// release.
+type pypiJSONResponse struct {
+	Info     pypiInfo                     `json:"info"`
+	Releases map[string][]pypiReleaseFile `json:"releases"`
+}
+
+type pypiInfo struct {
+	Version string `json:"version"`
+	Author  string `json:"author"`
+}
+
+type pypiReleaseFile struct {
+	UploadTimeISO8601 string `json:"upload_time_iso_8601"`
+	Yanked            bool   `json:"yanked"`
+	Digests           struct {
+		SHA256 string `json:"sha256"`
+	} `json:"digests"`
+}
+
+// fetchPyPIVersions fetches the published versions for pkgname from reg's
+// JSON API.
+func fetchPyPIVersions(reg registries.Registry, pkgname string) ([]string, error) {
+	url := strings.TrimSuffix(reg.URL, "/project") + "/pypi/" + pkgname + "/json"
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+	reg.ApplyAuth(req)
+	resp, err := reg.Client(0).Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("PyPI JSON API returned %s for %s", resp.Status, url)
+	}
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	var data pypiJSONResponse
+	if err := json.Unmarshal(body, &data); err != nil {
+		return nil, err
+	}
+	versions := make([]string, 0, len(data.Releases))
+	for v := range data.Releases {
+		versions = append(versions, v)
+	}
+	return versions, nil
+}
+
+// fetchPyPISha256 fetches the sha256 digests PyPI publishes for every file
+// (sdist and wheels) of pkgname at the given version, for comparison against
+// a requirements.txt --hash pin. Returns an empty slice with no error if
+// that version has no release files (and so nothing to hash).
+func fetchPyPISha256(reg registries.Registry, pkgname string, version string) ([]string, error) {
+	url := strings.TrimSuffix(reg.URL, "/project") + "/pypi/" + pkgname + "/json"
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+	reg.ApplyAuth(req)
+	resp, err := reg.Client(0).Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("PyPI JSON API returned %s for %s", resp.Status, url)
+	}
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	var data pypiJSONResponse
+	if err := json.Unmarshal(body, &data); err != nil {
+		return nil, err
+	}
+	files, ok := data.Releases[version]
+	if !ok || len(files) == 0 {
+		return nil, nil
+	}
+	digests := make([]string, 0, len(files))
+	for _, f := range files {
+		if f.Digests.SHA256 != "" {
+			digests = append(digests, f.Digests.SHA256)
+		}
+	}
+	return digests, nil
+}
+
+// pypiPackageInfo is the subset of a PyPI JSON API response surfaced in
+// PackageDetail.Metadata: the project's latest published version and
+// author, plus the upload time and yanked status of whichever release
+// matches the requirement's pinned version (falling back to the latest
+// release if version is empty or unpublished).
+type pypiPackageInfo struct {
+	Version    string
+	Author     string
+	UploadTime string
+	Yanked     bool
+}
+
+// fetchPyPIPackageInfo fetches the info/release metadata PyPI's JSON API
+// publishes for pkgname, describing whichever release matches version (or
+// the latest release if version is empty or that version has no files).
+func fetchPyPIPackageInfo(reg registries.Registry, pkgname string, version string) (pypiPackageInfo, error) {
+	url := strings.TrimSuffix(reg.URL, "/project") + "/pypi/" + pkgname + "/json"
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return pypiPackageInfo{}, err
+	}
+	reg.ApplyAuth(req)
+	resp, err := reg.Client(0).Do(req)
+	if err != nil {
+		return pypiPackageInfo{}, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return pypiPackageInfo{}, fmt.Errorf("PyPI JSON API returned %s for %s", resp.Status, url)
+	}
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return pypiPackageInfo{}, err
+	}
+	var data pypiJSONResponse
+	if err := json.Unmarshal(body, &data); err != nil {
+		return pypiPackageInfo{}, err
+	}
+	info := pypiPackageInfo{Version: data.Info.Version, Author: data.Info.Author}
+	files, ok := data.Releases[version]
+	if !ok || len(files) == 0 {
+		files = data.Releases[data.Info.Version]
+	}
+	if len(files) > 0 {
+		info.UploadTime = files[0].UploadTimeISO8601
+		info.Yanked = files[0].Yanked
+	}
+	return info, nil
+}
@@ -0,0 +1,505 @@
+// Package bitbucket scans repositories hosted on Bitbucket Cloud for
+// dependency confusion, the same way pkg/github scans repos hosted on
+// github.com. It talks to Bitbucket's REST API 2.0 directly over net/http
+// rather than pulling in a separate SDK dependency.
+//
+// Bitbucket Cloud has no single recursive-tree endpoint like GitHub's git
+// trees API, so file discovery walks the "src" endpoint one directory
+// level at a time, bounded to maxWalkDepth to avoid pathological repos.
+// Authentication is Bearer-token only (a repository or workspace access
+// token); classic username+app-password Basic auth is not implemented.
+package bitbucket
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/h0tak88r/confused/internal/resolvers"
+	"github.com/h0tak88r/confused/internal/types"
+	"github.com/h0tak88r/confused/pkg/config"
+	"github.com/h0tak88r/confused/pkg/logger"
+	"github.com/h0tak88r/confused/pkg/registries"
+	"golang.org/x/time/rate"
+)
+
+const (
+	apiBase     = "https://api.bitbucket.org/2.0"
+	maxWalkDepth = 6
+)
+
+// Client talks to the Bitbucket Cloud REST API 2.0.
+type Client struct {
+	token       string
+	httpClient  *http.Client
+	ctx         context.Context
+	config      *config.Config
+	logger      *logger.Logger
+	limiter     *rate.Limiter
+	registrySet *registries.Set
+}
+
+// New creates a new Bitbucket client, authenticating with a Bearer token
+// (a repository or workspace access token).
+func New(cfg *config.Config, log *logger.Logger, token string) *Client {
+	if token == "" {
+		log.Warn("No Bitbucket token provided, using unauthenticated requests (rate limited)")
+	}
+	return &Client{
+		token:      token,
+		httpClient: &http.Client{Timeout: cfg.GetTimeout()},
+		ctx:        context.Background(),
+		config:     cfg,
+		logger:     log,
+	}
+}
+
+// SetContext points the client at a cancellable root context, so SIGINT/
+// SIGTERM during a workspace scan stops in-flight repository and registry
+// calls instead of running to completion.
+func (c *Client) SetContext(ctx context.Context) {
+	c.ctx = ctx
+}
+
+// SetLimiter points this client's resolvers at a shared rate limiter.
+func (c *Client) SetLimiter(limiter *rate.Limiter) {
+	c.limiter = limiter
+}
+
+// SetRegistries points this client's resolvers at the configured
+// public/internal registry mirrors.
+func (c *Client) SetRegistries(regs *registries.Set) {
+	c.registrySet = regs
+}
+
+type bitbucketRepository struct {
+	Slug       string `json:"slug"`
+	FullName   string `json:"full_name"`
+	Mainbranch struct {
+		Name string `json:"name"`
+	} `json:"mainbranch"`
+}
+
+type bitbucketPage struct {
+	Next     string          `json:"next"`
+	Values   json.RawMessage `json:"values"`
+}
+
+type bitbucketBranch struct {
+	Name string `json:"name"`
+}
+
+type bitbucketSrcEntry struct {
+	Path string `json:"path"`
+	Type string `json:"type"` // "commit_file" or "commit_directory"
+	Size int64  `json:"size"`
+}
+
+// get performs an authenticated GET against the Bitbucket API and decodes
+// the JSON response body into out.
+func (c *Client) get(rawURL string, out interface{}) error {
+	req, err := http.NewRequestWithContext(c.ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return err
+	}
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("bitbucket API request to %s failed: %s", rawURL, resp.Status)
+	}
+
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+// getBody performs an authenticated GET and returns the raw response body.
+func (c *Client) getBody(rawURL string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(c.ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("bitbucket API request to %s failed: %s", rawURL, resp.Status)
+	}
+
+	buf := make([]byte, 0, 4096)
+	chunk := make([]byte, 4096)
+	for {
+		n, err := resp.Body.Read(chunk)
+		if n > 0 {
+			buf = append(buf, chunk[:n]...)
+		}
+		if err != nil {
+			break
+		}
+	}
+	return buf, nil
+}
+
+// ScanRepository scans a specific Bitbucket repository, identified as
+// "workspace/repo_slug".
+func (c *Client) ScanRepository(repo string, languages []string, safeSpaces []string, deep bool) ([]*types.ScanResult, error) {
+	parts := strings.Split(repo, "/")
+	if len(parts) != 2 {
+		return nil, fmt.Errorf("invalid repository format: %s (expected workspace/repo_slug)", repo)
+	}
+	workspace, slug := parts[0], parts[1]
+
+	c.logger.Info("Scanning repository: %s/%s", workspace, slug)
+
+	var repository bitbucketRepository
+	if err := c.get(fmt.Sprintf("%s/repositories/%s/%s", apiBase, workspace, slug), &repository); err != nil {
+		return nil, fmt.Errorf("failed to get repository: %w", err)
+	}
+
+	defaultBranch := repository.Mainbranch.Name
+	if defaultBranch == "" {
+		defaultBranch = "main"
+	}
+
+	results, err := c.scanBranch(workspace, slug, defaultBranch, languages, safeSpaces)
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan default branch: %w", err)
+	}
+
+	if deep {
+		branches, err := c.getBranches(workspace, slug)
+		if err != nil {
+			c.logger.Warn("Failed to get branches for deep scan: %v", err)
+		} else {
+			for _, branch := range branches {
+				if branch == defaultBranch {
+					continue
+				}
+				branchResults, err := c.scanBranch(workspace, slug, branch, languages, safeSpaces)
+				if err != nil {
+					c.logger.Warn("Failed to scan branch %s: %v", branch, err)
+					continue
+				}
+				results = append(results, branchResults...)
+			}
+		}
+	}
+
+	return results, nil
+}
+
+// ScanOrganization scans every repository in a Bitbucket workspace.
+func (c *Client) ScanOrganization(org string, languages []string, safeSpaces []string, maxRepos int, deep bool) ([]*types.ScanResult, error) {
+	c.logger.Info("Scanning workspace: %s", org)
+
+	repos, err := c.getWorkspaceRepos(org, maxRepos)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get workspace repositories: %w", err)
+	}
+	c.logger.Info("Found %d repositories", len(repos))
+
+	var allResults []*types.ScanResult
+	for _, repo := range repos {
+		if err := c.ctx.Err(); err != nil {
+			return allResults, err
+		}
+		repoResults, err := c.ScanRepository(repo.FullName, languages, safeSpaces, deep)
+		if err != nil {
+			c.logger.Warn("Failed to scan repository %s: %v", repo.FullName, err)
+			continue
+		}
+		allResults = append(allResults, repoResults...)
+	}
+
+	return allResults, nil
+}
+
+// getWorkspaceRepos gets all repositories for a workspace, following the
+// API's "next" pagination links until maxRepos is reached.
+func (c *Client) getWorkspaceRepos(workspace string, maxRepos int) ([]bitbucketRepository, error) {
+	var allRepos []bitbucketRepository
+	next := fmt.Sprintf("%s/repositories/%s?pagelen=100", apiBase, workspace)
+
+	for next != "" {
+		var page bitbucketPage
+		if err := c.get(next, &page); err != nil {
+			return nil, err
+		}
+		var repos []bitbucketRepository
+		if err := json.Unmarshal(page.Values, &repos); err != nil {
+			return nil, fmt.Errorf("failed to decode repository page: %w", err)
+		}
+		allRepos = append(allRepos, repos...)
+		if len(allRepos) >= maxRepos {
+			allRepos = allRepos[:maxRepos]
+			break
+		}
+		next = page.Next
+	}
+
+	return allRepos, nil
+}
+
+// getBranches gets all branch names for a repository, following the API's
+// "next" pagination links.
+func (c *Client) getBranches(workspace, slug string) ([]string, error) {
+	var branches []string
+	next := fmt.Sprintf("%s/repositories/%s/%s/refs/branches?pagelen=100", apiBase, workspace, slug)
+
+	for next != "" {
+		var page bitbucketPage
+		if err := c.get(next, &page); err != nil {
+			return nil, err
+		}
+		var branchList []bitbucketBranch
+		if err := json.Unmarshal(page.Values, &branchList); err != nil {
+			return nil, fmt.Errorf("failed to decode branch page: %w", err)
+		}
+		for _, b := range branchList {
+			branches = append(branches, b.Name)
+		}
+		next = page.Next
+	}
+
+	return branches, nil
+}
+
+// scanBranch scans a specific branch for dependency files.
+func (c *Client) scanBranch(workspace, slug, branch string, languages []string, safeSpaces []string) ([]*types.ScanResult, error) {
+	var results []*types.ScanResult
+
+	entries, err := c.walkSrc(workspace, slug, branch, "", 0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to walk source tree for branch %s: %w", branch, err)
+	}
+
+	dependencyFiles := c.findDependencyFiles(entries, languages)
+	c.logger.Debug("Found %d dependency files in branch %s", len(dependencyFiles), branch)
+
+	for _, file := range dependencyFiles {
+		result, err := c.scanDependencyFile(workspace, slug, branch, file, safeSpaces)
+		if err != nil {
+			c.logger.Warn("Failed to scan dependency file %s: %v", file.Path, err)
+			continue
+		}
+		if result != nil {
+			results = append(results, result)
+		}
+	}
+
+	return results, nil
+}
+
+// walkSrc recursively lists the directory at dirPath (root when empty),
+// bounded to maxWalkDepth, since Bitbucket's src endpoint lists one
+// directory level at a time rather than offering a recursive tree call.
+func (c *Client) walkSrc(workspace, slug, revision, dirPath string, depth int) ([]bitbucketSrcEntry, error) {
+	if depth > maxWalkDepth {
+		c.logger.Debug("Reached max walk depth (%d) at %s, skipping deeper entries", maxWalkDepth, dirPath)
+		return nil, nil
+	}
+
+	var all []bitbucketSrcEntry
+	next := fmt.Sprintf("%s/repositories/%s/%s/src/%s/%s?pagelen=100", apiBase, workspace, slug, url.PathEscape(revision), dirPath)
+
+	var dirs []string
+	for next != "" {
+		var page bitbucketPage
+		if err := c.get(next, &page); err != nil {
+			return nil, err
+		}
+		var entries []bitbucketSrcEntry
+		if err := json.Unmarshal(page.Values, &entries); err != nil {
+			return nil, fmt.Errorf("failed to decode src page: %w", err)
+		}
+		for _, e := range entries {
+			if e.Type == "commit_directory" {
+				dirs = append(dirs, e.Path)
+			} else {
+				all = append(all, e)
+			}
+		}
+		next = page.Next
+	}
+
+	for _, dir := range dirs {
+		children, err := c.walkSrc(workspace, slug, revision, dir, depth+1)
+		if err != nil {
+			c.logger.Warn("Failed to list directory %s: %v", dir, err)
+			continue
+		}
+		all = append(all, children...)
+	}
+
+	return all, nil
+}
+
+// findDependencyFiles finds dependency files in a flattened src listing.
+func (c *Client) findDependencyFiles(entries []bitbucketSrcEntry, languages []string) []bitbucketSrcEntry {
+	filePatterns := map[string][]string{
+		"npm":      {"package.json", "package-lock.json", "yarn.lock"},
+		"pip":      {"requirements.txt", "requirements-dev.txt", "setup.py", "pyproject.toml"},
+		"composer": {"composer.json", "composer.lock"},
+		"mvn":      {"pom.xml"},
+		"rubygems": {"Gemfile", "Gemfile.lock", "gems.rb"},
+	}
+
+	var allPatterns []string
+	for _, lang := range languages {
+		if patterns, exists := filePatterns[lang]; exists {
+			allPatterns = append(allPatterns, patterns...)
+		}
+	}
+
+	var dependencyFiles []bitbucketSrcEntry
+	for _, entry := range entries {
+		fileName := filepath.Base(entry.Path)
+		for _, pattern := range allPatterns {
+			if fileName == pattern {
+				dependencyFiles = append(dependencyFiles, entry)
+				break
+			}
+		}
+	}
+
+	return dependencyFiles
+}
+
+// scanDependencyFile scans a specific dependency file.
+func (c *Client) scanDependencyFile(workspace, slug, branch string, file bitbucketSrcEntry, safeSpaces []string) (*types.ScanResult, error) {
+	rawURL := fmt.Sprintf("%s/repositories/%s/%s/src/%s/%s", apiBase, workspace, slug, url.PathEscape(branch), file.Path)
+	content, err := c.getBody(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get file content: %w", err)
+	}
+
+	language := c.getLanguageFromFile(file.Path)
+	if language == "" {
+		return nil, fmt.Errorf("unknown language for file: %s", file.Path)
+	}
+
+	result := types.NewScanResult(
+		fmt.Sprintf("%s/%s:%s", workspace, slug, file.Path),
+		"bitbucket",
+		language,
+	)
+
+	resolver, err := c.getResolverForLanguage(language)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get resolver for language %s: %w", language, err)
+	}
+
+	tempFile, err := c.createTempFile(content)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create temporary file: %w", err)
+	}
+	defer os.Remove(tempFile)
+
+	if err := resolver.ReadPackagesFromFile(tempFile); err != nil {
+		return nil, fmt.Errorf("failed to read packages from file: %w", err)
+	}
+
+	if err := resolver.ResolveWithContext(c.ctx); err != nil {
+		return nil, fmt.Errorf("scan cancelled: %w", err)
+	}
+	vulnerablePackages := resolver.PackagesNotInPublic()
+	vulnerablePackages = c.removeSafe(vulnerablePackages, safeSpaces)
+
+	for _, pkg := range vulnerablePackages {
+		result.AddVulnerable(pkg)
+	}
+
+	result.Metadata["file_path"] = file.Path
+	result.Metadata["file_size"] = file.Size
+
+	result.Finalize()
+	return result, nil
+}
+
+// getLanguageFromFile determines the language from file path.
+func (c *Client) getLanguageFromFile(filePath string) string {
+	fileName := filepath.Base(filePath)
+	fileLanguageMap := map[string]string{
+		"package.json":         "npm",
+		"package-lock.json":    "npm",
+		"yarn.lock":            "npm",
+		"requirements.txt":     "pip",
+		"requirements-dev.txt": "pip",
+		"setup.py":             "pip",
+		"pyproject.toml":       "pip",
+		"composer.json":        "composer",
+		"composer.lock":        "composer",
+		"pom.xml":              "mvn",
+		"Gemfile":              "rubygems",
+		"Gemfile.lock":         "rubygems",
+		"gems.rb":              "rubygems",
+	}
+	return fileLanguageMap[fileName]
+}
+
+// createTempFile creates a temporary file with the given content.
+func (c *Client) createTempFile(content []byte) (string, error) {
+	tempFile, err := os.CreateTemp("", "confused-bitbucket-*.tmp")
+	if err != nil {
+		return "", err
+	}
+	if _, err := tempFile.Write(content); err != nil {
+		tempFile.Close()
+		os.Remove(tempFile.Name())
+		return "", err
+	}
+	if err := tempFile.Close(); err != nil {
+		os.Remove(tempFile.Name())
+		return "", err
+	}
+	return tempFile.Name(), nil
+}
+
+// getResolverForLanguage returns a resolver for the given language.
+func (c *Client) getResolverForLanguage(language string) (types.PackageResolver, error) {
+	return resolvers.GetResolverForLanguageWithContext(language, c.config.Verbose, c.ctx, c.limiter, c.registrySet, c.logger, c.config.Workers)
+}
+
+// removeSafe removes known-safe package names from the slice.
+func (c *Client) removeSafe(packages []string, safeSpaces []string) []string {
+	if len(safeSpaces) == 0 {
+		return packages
+	}
+	retSlice := []string{}
+	for _, pkg := range packages {
+		ignored := false
+		for _, safeSpace := range safeSpaces {
+			ok, err := filepath.Match(safeSpace, pkg)
+			if err != nil {
+				c.logger.Warn("Encountered an error while trying to match a known-safe namespace %s: %v", safeSpace, err)
+				continue
+			}
+			if ok {
+				ignored = true
+				break
+			}
+		}
+		if !ignored {
+			retSlice = append(retSlice, pkg)
+		}
+	}
+	return retSlice
+}
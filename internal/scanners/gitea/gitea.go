@@ -0,0 +1,448 @@
+// Package gitea scans repositories hosted on a Gitea instance for
+// dependency confusion, the same way pkg/github scans repos hosted on
+// github.com. Gitea's API v1 is deliberately GitHub-compatible, so this
+// client talks to it directly over net/http rather than pulling in a
+// separate SDK dependency.
+package gitea
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/h0tak88r/confused/internal/resolvers"
+	"github.com/h0tak88r/confused/internal/types"
+	"github.com/h0tak88r/confused/pkg/config"
+	"github.com/h0tak88r/confused/pkg/logger"
+	"github.com/h0tak88r/confused/pkg/registries"
+	"golang.org/x/time/rate"
+)
+
+// Client talks to a Gitea instance's REST API v1.
+type Client struct {
+	baseURL     string
+	token       string
+	httpClient  *http.Client
+	ctx         context.Context
+	config      *config.Config
+	logger      *logger.Logger
+	limiter     *rate.Limiter
+	registrySet *registries.Set
+}
+
+// New creates a new Gitea client. baseURL is the instance root, e.g.
+// "https://gitea.example.com" (defaults to "https://gitea.com" if empty).
+func New(cfg *config.Config, log *logger.Logger, baseURL string, token string) *Client {
+	if baseURL == "" {
+		baseURL = "https://gitea.com"
+	}
+	if token == "" {
+		log.Warn("No Gitea token provided, using unauthenticated requests (rate limited)")
+	}
+	return &Client{
+		baseURL:    strings.TrimSuffix(baseURL, "/"),
+		token:      token,
+		httpClient: &http.Client{Timeout: cfg.GetTimeout()},
+		ctx:        context.Background(),
+		config:     cfg,
+		logger:     log,
+	}
+}
+
+// SetContext points the client at a cancellable root context, so SIGINT/
+// SIGTERM during an org scan stops in-flight repository and registry calls
+// instead of running to completion.
+func (c *Client) SetContext(ctx context.Context) {
+	c.ctx = ctx
+}
+
+// SetLimiter points this client's resolvers at a shared rate limiter.
+func (c *Client) SetLimiter(limiter *rate.Limiter) {
+	c.limiter = limiter
+}
+
+// SetRegistries points this client's resolvers at the configured
+// public/internal registry mirrors.
+func (c *Client) SetRegistries(regs *registries.Set) {
+	c.registrySet = regs
+}
+
+type giteaRepository struct {
+	DefaultBranch string `json:"default_branch"`
+	FullName      string `json:"full_name"`
+}
+
+type giteaBranch struct {
+	Name string `json:"name"`
+}
+
+type giteaTreeEntry struct {
+	Path string `json:"path"`
+	Type string `json:"type"`
+	SHA  string `json:"sha"`
+	Size int64  `json:"size"`
+}
+
+type giteaTree struct {
+	Tree []giteaTreeEntry `json:"tree"`
+}
+
+type giteaBlob struct {
+	Content  string `json:"content"`
+	Encoding string `json:"encoding"`
+}
+
+// get performs an authenticated GET against the Gitea API and decodes the
+// JSON response body into out.
+func (c *Client) get(path string, out interface{}) error {
+	req, err := http.NewRequestWithContext(c.ctx, http.MethodGet, c.baseURL+path, nil)
+	if err != nil {
+		return err
+	}
+	if c.token != "" {
+		req.Header.Set("Authorization", "token "+c.token)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("gitea API request to %s failed: %s", path, resp.Status)
+	}
+
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+// ScanRepository scans a specific Gitea repository.
+func (c *Client) ScanRepository(repo string, languages []string, safeSpaces []string, deep bool) ([]*types.ScanResult, error) {
+	parts := strings.Split(repo, "/")
+	if len(parts) != 2 {
+		return nil, fmt.Errorf("invalid repository format: %s (expected owner/repo)", repo)
+	}
+	owner, repoName := parts[0], parts[1]
+
+	c.logger.Info("Scanning repository: %s/%s", owner, repoName)
+
+	var repository giteaRepository
+	if err := c.get(fmt.Sprintf("/api/v1/repos/%s/%s", owner, repoName), &repository); err != nil {
+		return nil, fmt.Errorf("failed to get repository: %w", err)
+	}
+
+	defaultBranch := repository.DefaultBranch
+	if defaultBranch == "" {
+		defaultBranch = "main"
+	}
+
+	results, err := c.scanBranch(owner, repoName, defaultBranch, languages, safeSpaces)
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan default branch: %w", err)
+	}
+
+	if deep {
+		branches, err := c.getBranches(owner, repoName)
+		if err != nil {
+			c.logger.Warn("Failed to get branches for deep scan: %v", err)
+		} else {
+			for _, branch := range branches {
+				if branch == defaultBranch {
+					continue
+				}
+				branchResults, err := c.scanBranch(owner, repoName, branch, languages, safeSpaces)
+				if err != nil {
+					c.logger.Warn("Failed to scan branch %s: %v", branch, err)
+					continue
+				}
+				results = append(results, branchResults...)
+			}
+		}
+	}
+
+	return results, nil
+}
+
+// ScanOrganization scans all repositories in a Gitea organization.
+func (c *Client) ScanOrganization(org string, languages []string, safeSpaces []string, maxRepos int, deep bool) ([]*types.ScanResult, error) {
+	c.logger.Info("Scanning organization: %s", org)
+
+	repos, err := c.getOrganizationRepos(org, maxRepos)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get organization repositories: %w", err)
+	}
+	c.logger.Info("Found %d repositories", len(repos))
+
+	var allResults []*types.ScanResult
+	for _, repo := range repos {
+		if err := c.ctx.Err(); err != nil {
+			return allResults, err
+		}
+		repoResults, err := c.ScanRepository(repo.FullName, languages, safeSpaces, deep)
+		if err != nil {
+			c.logger.Warn("Failed to scan repository %s: %v", repo.FullName, err)
+			continue
+		}
+		allResults = append(allResults, repoResults...)
+	}
+
+	return allResults, nil
+}
+
+// getOrganizationRepos gets all repositories for an organization, paginating
+// until maxRepos is reached or the instance returns a short page.
+func (c *Client) getOrganizationRepos(org string, maxRepos int) ([]giteaRepository, error) {
+	var allRepos []giteaRepository
+	page := 1
+	limit := 50
+
+	for {
+		var repos []giteaRepository
+		path := fmt.Sprintf("/api/v1/orgs/%s/repos?limit=%d&page=%d", url.PathEscape(org), limit, page)
+		if err := c.get(path, &repos); err != nil {
+			return nil, err
+		}
+		if len(repos) == 0 {
+			break
+		}
+		allRepos = append(allRepos, repos...)
+		if len(allRepos) >= maxRepos {
+			allRepos = allRepos[:maxRepos]
+			break
+		}
+		if len(repos) < limit {
+			break
+		}
+		page++
+	}
+
+	return allRepos, nil
+}
+
+// getBranches gets all branch names for a repository.
+func (c *Client) getBranches(owner, repo string) ([]string, error) {
+	var branches []string
+	page := 1
+	limit := 50
+
+	for {
+		var branchList []giteaBranch
+		path := fmt.Sprintf("/api/v1/repos/%s/%s/branches?limit=%d&page=%d", owner, repo, limit, page)
+		if err := c.get(path, &branchList); err != nil {
+			return nil, err
+		}
+		if len(branchList) == 0 {
+			break
+		}
+		for _, b := range branchList {
+			branches = append(branches, b.Name)
+		}
+		if len(branchList) < limit {
+			break
+		}
+		page++
+	}
+
+	return branches, nil
+}
+
+// scanBranch scans a specific branch for dependency files.
+func (c *Client) scanBranch(owner, repo, branch string, languages []string, safeSpaces []string) ([]*types.ScanResult, error) {
+	var results []*types.ScanResult
+
+	var tree giteaTree
+	path := fmt.Sprintf("/api/v1/repos/%s/%s/git/trees/%s?recursive=true", owner, repo, url.PathEscape(branch))
+	if err := c.get(path, &tree); err != nil {
+		return nil, fmt.Errorf("failed to get tree for branch %s: %w", branch, err)
+	}
+
+	dependencyFiles := c.findDependencyFiles(tree.Tree, languages)
+	c.logger.Debug("Found %d dependency files in branch %s", len(dependencyFiles), branch)
+
+	for _, file := range dependencyFiles {
+		result, err := c.scanDependencyFile(owner, repo, file, safeSpaces)
+		if err != nil {
+			c.logger.Warn("Failed to scan dependency file %s: %v", file.Path, err)
+			continue
+		}
+		if result != nil {
+			results = append(results, result)
+		}
+	}
+
+	return results, nil
+}
+
+// findDependencyFiles finds dependency files in a repository tree listing.
+func (c *Client) findDependencyFiles(entries []giteaTreeEntry, languages []string) []giteaTreeEntry {
+	filePatterns := map[string][]string{
+		"npm":      {"package.json", "package-lock.json", "yarn.lock"},
+		"pip":      {"requirements.txt", "requirements-dev.txt", "setup.py", "pyproject.toml"},
+		"composer": {"composer.json", "composer.lock"},
+		"mvn":      {"pom.xml"},
+		"rubygems": {"Gemfile", "Gemfile.lock", "gems.rb"},
+	}
+
+	var allPatterns []string
+	for _, lang := range languages {
+		if patterns, exists := filePatterns[lang]; exists {
+			allPatterns = append(allPatterns, patterns...)
+		}
+	}
+
+	var dependencyFiles []giteaTreeEntry
+	for _, entry := range entries {
+		if entry.Type != "blob" {
+			continue
+		}
+		fileName := filepath.Base(entry.Path)
+		for _, pattern := range allPatterns {
+			if fileName == pattern {
+				dependencyFiles = append(dependencyFiles, entry)
+				break
+			}
+		}
+	}
+
+	return dependencyFiles
+}
+
+// scanDependencyFile scans a specific dependency file.
+func (c *Client) scanDependencyFile(owner, repo string, file giteaTreeEntry, safeSpaces []string) (*types.ScanResult, error) {
+	content, err := c.getFileContent(owner, repo, file.SHA)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get file content: %w", err)
+	}
+
+	language := c.getLanguageFromFile(file.Path)
+	if language == "" {
+		return nil, fmt.Errorf("unknown language for file: %s", file.Path)
+	}
+
+	result := types.NewScanResult(
+		fmt.Sprintf("%s/%s:%s", owner, repo, file.Path),
+		"gitea",
+		language,
+	)
+
+	resolver, err := c.getResolverForLanguage(language)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get resolver for language %s: %w", language, err)
+	}
+
+	tempFile, err := c.createTempFile(content)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create temporary file: %w", err)
+	}
+	defer os.Remove(tempFile)
+
+	if err := resolver.ReadPackagesFromFile(tempFile); err != nil {
+		return nil, fmt.Errorf("failed to read packages from file: %w", err)
+	}
+
+	if err := resolver.ResolveWithContext(c.ctx); err != nil {
+		return nil, fmt.Errorf("scan cancelled: %w", err)
+	}
+	vulnerablePackages := resolver.PackagesNotInPublic()
+	vulnerablePackages = c.removeSafe(vulnerablePackages, safeSpaces)
+
+	for _, pkg := range vulnerablePackages {
+		result.AddVulnerable(pkg)
+	}
+
+	result.Metadata["file_path"] = file.Path
+	result.Metadata["file_sha"] = file.SHA
+	result.Metadata["file_size"] = file.Size
+
+	result.Finalize()
+	return result, nil
+}
+
+// getFileContent gets the content of a file via the blobs endpoint.
+func (c *Client) getFileContent(owner, repo, sha string) ([]byte, error) {
+	var blob giteaBlob
+	path := fmt.Sprintf("/api/v1/repos/%s/%s/git/blobs/%s", owner, repo, sha)
+	if err := c.get(path, &blob); err != nil {
+		return nil, err
+	}
+	if blob.Encoding != "base64" {
+		return []byte(blob.Content), nil
+	}
+	return base64.StdEncoding.DecodeString(blob.Content)
+}
+
+// getLanguageFromFile determines the language from file path.
+func (c *Client) getLanguageFromFile(filePath string) string {
+	fileName := filepath.Base(filePath)
+	fileLanguageMap := map[string]string{
+		"package.json":         "npm",
+		"package-lock.json":    "npm",
+		"yarn.lock":            "npm",
+		"requirements.txt":     "pip",
+		"requirements-dev.txt": "pip",
+		"setup.py":             "pip",
+		"pyproject.toml":       "pip",
+		"composer.json":        "composer",
+		"composer.lock":        "composer",
+		"pom.xml":              "mvn",
+		"Gemfile":              "rubygems",
+		"Gemfile.lock":         "rubygems",
+		"gems.rb":              "rubygems",
+	}
+	return fileLanguageMap[fileName]
+}
+
+// createTempFile creates a temporary file with the given content.
+func (c *Client) createTempFile(content []byte) (string, error) {
+	tempFile, err := os.CreateTemp("", "confused-gitea-*.tmp")
+	if err != nil {
+		return "", err
+	}
+	if _, err := tempFile.Write(content); err != nil {
+		tempFile.Close()
+		os.Remove(tempFile.Name())
+		return "", err
+	}
+	if err := tempFile.Close(); err != nil {
+		os.Remove(tempFile.Name())
+		return "", err
+	}
+	return tempFile.Name(), nil
+}
+
+// getResolverForLanguage returns a resolver for the given language.
+func (c *Client) getResolverForLanguage(language string) (types.PackageResolver, error) {
+	return resolvers.GetResolverForLanguageWithContext(language, c.config.Verbose, c.ctx, c.limiter, c.registrySet, c.logger, c.config.Workers)
+}
+
+// removeSafe removes known-safe package names from the slice.
+func (c *Client) removeSafe(packages []string, safeSpaces []string) []string {
+	if len(safeSpaces) == 0 {
+		return packages
+	}
+	retSlice := []string{}
+	for _, pkg := range packages {
+		ignored := false
+		for _, safeSpace := range safeSpaces {
+			ok, err := filepath.Match(safeSpace, pkg)
+			if err != nil {
+				c.logger.Warn("Encountered an error while trying to match a known-safe namespace %s: %v", safeSpace, err)
+				continue
+			}
+			if ok {
+				ignored = true
+				break
+			}
+		}
+		if !ignored {
+			retSlice = append(retSlice, pkg)
+		}
+	}
+	return retSlice
+}
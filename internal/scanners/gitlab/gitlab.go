@@ -0,0 +1,478 @@
+// Package gitlab scans repositories hosted on a GitLab instance for
+// dependency confusion, the same way pkg/github scans repos hosted on
+// github.com. It talks to GitLab's REST API v4 directly over net/http
+// rather than pulling in a separate SDK dependency.
+package gitlab
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/h0tak88r/confused/internal/resolvers"
+	"github.com/h0tak88r/confused/internal/types"
+	"github.com/h0tak88r/confused/pkg/config"
+	"github.com/h0tak88r/confused/pkg/logger"
+	"github.com/h0tak88r/confused/pkg/registries"
+	"golang.org/x/time/rate"
+)
+
+// Client talks to a GitLab instance's REST API v4.
+type Client struct {
+	baseURL     string
+	token       string
+	httpClient  *http.Client
+	ctx         context.Context
+	config      *config.Config
+	logger      *logger.Logger
+	limiter     *rate.Limiter
+	registrySet *registries.Set
+}
+
+// New creates a new GitLab client. baseURL is the instance root, e.g.
+// "https://gitlab.example.com" (defaults to "https://gitlab.com" if empty).
+func New(cfg *config.Config, log *logger.Logger, baseURL string, token string) *Client {
+	if baseURL == "" {
+		baseURL = "https://gitlab.com"
+	}
+	if token == "" {
+		log.Warn("No GitLab token provided, using unauthenticated requests (rate limited)")
+	}
+	return &Client{
+		baseURL:    strings.TrimSuffix(baseURL, "/"),
+		token:      token,
+		httpClient: &http.Client{Timeout: cfg.GetTimeout()},
+		ctx:        context.Background(),
+		config:     cfg,
+		logger:     log,
+	}
+}
+
+// SetContext points the client at a cancellable root context, so SIGINT/
+// SIGTERM during a group scan stops in-flight project and registry calls
+// instead of running to completion.
+func (c *Client) SetContext(ctx context.Context) {
+	c.ctx = ctx
+}
+
+// SetLimiter points this client's resolvers at a shared rate limiter.
+func (c *Client) SetLimiter(limiter *rate.Limiter) {
+	c.limiter = limiter
+}
+
+// SetRegistries points this client's resolvers at the configured
+// public/internal registry mirrors.
+func (c *Client) SetRegistries(regs *registries.Set) {
+	c.registrySet = regs
+}
+
+type gitlabProject struct {
+	ID            int    `json:"id"`
+	PathWithNS    string `json:"path_with_namespace"`
+	DefaultBranch string `json:"default_branch"`
+}
+
+type gitlabBranch struct {
+	Name string `json:"name"`
+}
+
+type gitlabTreeEntry struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	Type string `json:"type"` // "blob" or "tree"
+	Path string `json:"path"`
+}
+
+// get performs an authenticated GET against the GitLab API and decodes the
+// JSON response body into out.
+func (c *Client) get(path string, out interface{}) error {
+	req, err := http.NewRequestWithContext(c.ctx, http.MethodGet, c.baseURL+path, nil)
+	if err != nil {
+		return err
+	}
+	if c.token != "" {
+		req.Header.Set("PRIVATE-TOKEN", c.token)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("gitlab API request to %s failed: %s", path, resp.Status)
+	}
+
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+// getRaw performs an authenticated GET and returns the raw response body.
+func (c *Client) getRaw(path string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(c.ctx, http.MethodGet, c.baseURL+path, nil)
+	if err != nil {
+		return nil, err
+	}
+	if c.token != "" {
+		req.Header.Set("PRIVATE-TOKEN", c.token)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("gitlab API request to %s failed: %s", path, resp.Status)
+	}
+
+	buf := make([]byte, 0, 4096)
+	chunk := make([]byte, 4096)
+	for {
+		n, err := resp.Body.Read(chunk)
+		if n > 0 {
+			buf = append(buf, chunk[:n]...)
+		}
+		if err != nil {
+			break
+		}
+	}
+	return buf, nil
+}
+
+// ScanRepository scans a specific GitLab project, identified by its numeric
+// ID or its "group/project" path.
+func (c *Client) ScanRepository(repo string, languages []string, safeSpaces []string, deep bool) ([]*types.ScanResult, error) {
+	c.logger.Info("Scanning project: %s", repo)
+
+	var project gitlabProject
+	if err := c.get(fmt.Sprintf("/api/v4/projects/%s", url.PathEscape(repo)), &project); err != nil {
+		return nil, fmt.Errorf("failed to get project: %w", err)
+	}
+
+	defaultBranch := project.DefaultBranch
+	if defaultBranch == "" {
+		defaultBranch = "main"
+	}
+
+	results, err := c.scanBranch(project.ID, repo, defaultBranch, languages, safeSpaces)
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan default branch: %w", err)
+	}
+
+	if deep {
+		branches, err := c.getBranches(project.ID)
+		if err != nil {
+			c.logger.Warn("Failed to get branches for deep scan: %v", err)
+		} else {
+			for _, branch := range branches {
+				if branch == defaultBranch {
+					continue
+				}
+				branchResults, err := c.scanBranch(project.ID, repo, branch, languages, safeSpaces)
+				if err != nil {
+					c.logger.Warn("Failed to scan branch %s: %v", branch, err)
+					continue
+				}
+				results = append(results, branchResults...)
+			}
+		}
+	}
+
+	return results, nil
+}
+
+// ScanOrganization scans every project in a GitLab group, identified by its
+// numeric ID or its full path.
+func (c *Client) ScanOrganization(org string, languages []string, safeSpaces []string, maxRepos int, deep bool) ([]*types.ScanResult, error) {
+	c.logger.Info("Scanning group: %s", org)
+
+	projects, err := c.getGroupProjects(org, maxRepos)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get group projects: %w", err)
+	}
+	c.logger.Info("Found %d projects", len(projects))
+
+	var allResults []*types.ScanResult
+	for _, project := range projects {
+		if err := c.ctx.Err(); err != nil {
+			return allResults, err
+		}
+		repoResults, err := c.ScanRepository(project.PathWithNS, languages, safeSpaces, deep)
+		if err != nil {
+			c.logger.Warn("Failed to scan project %s: %v", project.PathWithNS, err)
+			continue
+		}
+		allResults = append(allResults, repoResults...)
+	}
+
+	return allResults, nil
+}
+
+// getGroupProjects gets all projects for a group, paginating until maxRepos
+// is reached or the instance returns a short page.
+func (c *Client) getGroupProjects(group string, maxRepos int) ([]gitlabProject, error) {
+	var allProjects []gitlabProject
+	page := 1
+	perPage := 100
+
+	for {
+		var projects []gitlabProject
+		path := fmt.Sprintf("/api/v4/groups/%s/projects?per_page=%d&page=%d&include_subgroups=true", url.PathEscape(group), perPage, page)
+		if err := c.get(path, &projects); err != nil {
+			return nil, err
+		}
+		if len(projects) == 0 {
+			break
+		}
+		allProjects = append(allProjects, projects...)
+		if len(allProjects) >= maxRepos {
+			allProjects = allProjects[:maxRepos]
+			break
+		}
+		if len(projects) < perPage {
+			break
+		}
+		page++
+	}
+
+	return allProjects, nil
+}
+
+// getBranches gets all branch names for a project.
+func (c *Client) getBranches(projectID int) ([]string, error) {
+	var branches []string
+	page := 1
+	perPage := 100
+
+	for {
+		var branchList []gitlabBranch
+		path := fmt.Sprintf("/api/v4/projects/%d/repository/branches?per_page=%d&page=%d", projectID, perPage, page)
+		if err := c.get(path, &branchList); err != nil {
+			return nil, err
+		}
+		if len(branchList) == 0 {
+			break
+		}
+		for _, b := range branchList {
+			branches = append(branches, b.Name)
+		}
+		if len(branchList) < perPage {
+			break
+		}
+		page++
+	}
+
+	return branches, nil
+}
+
+// scanBranch scans a specific branch for dependency files.
+func (c *Client) scanBranch(projectID int, repoLabel, branch string, languages []string, safeSpaces []string) ([]*types.ScanResult, error) {
+	var results []*types.ScanResult
+
+	entries, err := c.getTree(projectID, branch)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tree for branch %s: %w", branch, err)
+	}
+
+	dependencyFiles := c.findDependencyFiles(entries, languages)
+	c.logger.Debug("Found %d dependency files in branch %s", len(dependencyFiles), branch)
+
+	for _, file := range dependencyFiles {
+		result, err := c.scanDependencyFile(projectID, repoLabel, branch, file, safeSpaces)
+		if err != nil {
+			c.logger.Warn("Failed to scan dependency file %s: %v", file.Path, err)
+			continue
+		}
+		if result != nil {
+			results = append(results, result)
+		}
+	}
+
+	return results, nil
+}
+
+// getTree gets the full recursive repository tree at ref.
+func (c *Client) getTree(projectID int, ref string) ([]gitlabTreeEntry, error) {
+	var allEntries []gitlabTreeEntry
+	page := 1
+	perPage := 100
+
+	for {
+		var entries []gitlabTreeEntry
+		path := fmt.Sprintf("/api/v4/projects/%d/repository/tree?ref=%s&recursive=true&per_page=%d&page=%d", projectID, url.QueryEscape(ref), perPage, page)
+		if err := c.get(path, &entries); err != nil {
+			return nil, err
+		}
+		if len(entries) == 0 {
+			break
+		}
+		allEntries = append(allEntries, entries...)
+		if len(entries) < perPage {
+			break
+		}
+		page++
+	}
+
+	return allEntries, nil
+}
+
+// findDependencyFiles finds dependency files in a repository tree listing.
+func (c *Client) findDependencyFiles(entries []gitlabTreeEntry, languages []string) []gitlabTreeEntry {
+	filePatterns := map[string][]string{
+		"npm":      {"package.json", "package-lock.json", "yarn.lock"},
+		"pip":      {"requirements.txt", "requirements-dev.txt", "setup.py", "pyproject.toml"},
+		"composer": {"composer.json", "composer.lock"},
+		"mvn":      {"pom.xml"},
+		"rubygems": {"Gemfile", "Gemfile.lock", "gems.rb"},
+	}
+
+	var allPatterns []string
+	for _, lang := range languages {
+		if patterns, exists := filePatterns[lang]; exists {
+			allPatterns = append(allPatterns, patterns...)
+		}
+	}
+
+	var dependencyFiles []gitlabTreeEntry
+	for _, entry := range entries {
+		if entry.Type != "blob" {
+			continue
+		}
+		fileName := filepath.Base(entry.Path)
+		for _, pattern := range allPatterns {
+			if fileName == pattern {
+				dependencyFiles = append(dependencyFiles, entry)
+				break
+			}
+		}
+	}
+
+	return dependencyFiles
+}
+
+// scanDependencyFile scans a specific dependency file.
+func (c *Client) scanDependencyFile(projectID int, repoLabel, branch string, file gitlabTreeEntry, safeSpaces []string) (*types.ScanResult, error) {
+	content, err := c.getRaw(fmt.Sprintf("/api/v4/projects/%d/repository/files/%s/raw?ref=%s", projectID, url.PathEscape(file.Path), url.QueryEscape(branch)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get file content: %w", err)
+	}
+
+	language := c.getLanguageFromFile(file.Path)
+	if language == "" {
+		return nil, fmt.Errorf("unknown language for file: %s", file.Path)
+	}
+
+	result := types.NewScanResult(
+		fmt.Sprintf("%s:%s", repoLabel, file.Path),
+		"gitlab",
+		language,
+	)
+
+	resolver, err := c.getResolverForLanguage(language)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get resolver for language %s: %w", language, err)
+	}
+
+	tempFile, err := c.createTempFile(content)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create temporary file: %w", err)
+	}
+	defer os.Remove(tempFile)
+
+	if err := resolver.ReadPackagesFromFile(tempFile); err != nil {
+		return nil, fmt.Errorf("failed to read packages from file: %w", err)
+	}
+
+	if err := resolver.ResolveWithContext(c.ctx); err != nil {
+		return nil, fmt.Errorf("scan cancelled: %w", err)
+	}
+	vulnerablePackages := resolver.PackagesNotInPublic()
+	vulnerablePackages = c.removeSafe(vulnerablePackages, safeSpaces)
+
+	for _, pkg := range vulnerablePackages {
+		result.AddVulnerable(pkg)
+	}
+
+	result.Metadata["file_path"] = file.Path
+	result.Metadata["blob_id"] = file.ID
+
+	result.Finalize()
+	return result, nil
+}
+
+// getLanguageFromFile determines the language from file path.
+func (c *Client) getLanguageFromFile(filePath string) string {
+	fileName := filepath.Base(filePath)
+	fileLanguageMap := map[string]string{
+		"package.json":         "npm",
+		"package-lock.json":    "npm",
+		"yarn.lock":            "npm",
+		"requirements.txt":     "pip",
+		"requirements-dev.txt": "pip",
+		"setup.py":             "pip",
+		"pyproject.toml":       "pip",
+		"composer.json":        "composer",
+		"composer.lock":        "composer",
+		"pom.xml":              "mvn",
+		"Gemfile":              "rubygems",
+		"Gemfile.lock":         "rubygems",
+		"gems.rb":              "rubygems",
+	}
+	return fileLanguageMap[fileName]
+}
+
+// createTempFile creates a temporary file with the given content.
+func (c *Client) createTempFile(content []byte) (string, error) {
+	tempFile, err := os.CreateTemp("", "confused-gitlab-*.tmp")
+	if err != nil {
+		return "", err
+	}
+	if _, err := tempFile.Write(content); err != nil {
+		tempFile.Close()
+		os.Remove(tempFile.Name())
+		return "", err
+	}
+	if err := tempFile.Close(); err != nil {
+		os.Remove(tempFile.Name())
+		return "", err
+	}
+	return tempFile.Name(), nil
+}
+
+// getResolverForLanguage returns a resolver for the given language.
+func (c *Client) getResolverForLanguage(language string) (types.PackageResolver, error) {
+	return resolvers.GetResolverForLanguageWithContext(language, c.config.Verbose, c.ctx, c.limiter, c.registrySet, c.logger, c.config.Workers)
+}
+
+// removeSafe removes known-safe package names from the slice.
+func (c *Client) removeSafe(packages []string, safeSpaces []string) []string {
+	if len(safeSpaces) == 0 {
+		return packages
+	}
+	retSlice := []string{}
+	for _, pkg := range packages {
+		ignored := false
+		for _, safeSpace := range safeSpaces {
+			ok, err := filepath.Match(safeSpace, pkg)
+			if err != nil {
+				c.logger.Warn("Encountered an error while trying to match a known-safe namespace %s: %v", safeSpace, err)
+				continue
+			}
+			if ok {
+				ignored = true
+				break
+			}
+		}
+		if !ignored {
+			retSlice = append(retSlice, pkg)
+		}
+	}
+	return retSlice
+}
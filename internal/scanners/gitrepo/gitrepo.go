@@ -0,0 +1,440 @@
+// Package gitrepo scans local git checkouts and remote clones for
+// dependency confusion the same way pkg/github scans repos hosted on
+// github.com, so private repos on other hosts, air-gapped vendored source
+// drops, and CI checkouts can be scanned without a GitHub API token or
+// rate limit.
+package gitrepo
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/plumbing/transport"
+	gittransporthttp "github.com/go-git/go-git/v5/plumbing/transport/http"
+	"github.com/h0tak88r/confused/internal/resolvers"
+	"github.com/h0tak88r/confused/internal/types"
+	"github.com/h0tak88r/confused/pkg/config"
+	"github.com/h0tak88r/confused/pkg/logger"
+	"github.com/h0tak88r/confused/pkg/registries"
+	"golang.org/x/time/rate"
+)
+
+// Client scans local git checkouts, file:// paths, and https:///ssh://
+// clone URLs for dependency confusion.
+type Client struct {
+	ctx         context.Context
+	config      *config.Config
+	logger      *logger.Logger
+	limiter     *rate.Limiter
+	registrySet *registries.Set
+	depth       int
+}
+
+// New creates a new git repository client.
+func New(cfg *config.Config, log *logger.Logger) *Client {
+	return &Client{
+		ctx:    context.Background(),
+		config: cfg,
+		logger: log,
+	}
+}
+
+// SetContext points the client at a cancellable root context, so SIGINT/
+// SIGTERM during a scan stops in-flight clones and registry calls instead
+// of running to completion.
+func (c *Client) SetContext(ctx context.Context) {
+	c.ctx = ctx
+}
+
+// SetLimiter points this client's resolvers at a shared rate limiter.
+func (c *Client) SetLimiter(limiter *rate.Limiter) {
+	c.limiter = limiter
+}
+
+// SetRegistries points this client's resolvers at the configured
+// public/internal registry mirrors.
+func (c *Client) SetRegistries(regs *registries.Set) {
+	c.registrySet = regs
+}
+
+// SetCloneDepth sets the --depth passed to remote clones (0 clones full
+// history, matching go-git's default).
+func (c *Client) SetCloneDepth(depth int) {
+	c.depth = depth
+}
+
+// ScanRepository scans a single target, which may be a local path, a
+// file:// path, or an https:// or ssh:// clone URL. branch selects which
+// ref to check out as HEAD; an empty branch uses the repository's default.
+func (c *Client) ScanRepository(target string, branch string, languages []string, safeSpaces []string, deep bool) ([]*types.ScanResult, error) {
+	c.logger.Info("Scanning git repository: %s", target)
+
+	repo, cleanup, err := c.open(target, branch)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s: %w", target, err)
+	}
+	defer cleanup()
+
+	head, err := repo.Head()
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve HEAD for %s: %w", target, err)
+	}
+
+	results, err := c.scanBranch(repo, target, head.Name().Short(), head.Hash(), languages, safeSpaces)
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan HEAD: %w", err)
+	}
+
+	// If deep scan is enabled, scan every other branch ref too.
+	if deep {
+		refs, err := repo.References()
+		if err != nil {
+			c.logger.Warn("Failed to list references for deep scan: %v", err)
+			return results, nil
+		}
+
+		err = refs.ForEach(func(ref *plumbing.Reference) error {
+			if !ref.Name().IsBranch() || ref.Name().Short() == head.Name().Short() {
+				return nil
+			}
+
+			branchResults, err := c.scanBranch(repo, target, ref.Name().Short(), ref.Hash(), languages, safeSpaces)
+			if err != nil {
+				c.logger.Warn("Failed to scan branch %s: %v", ref.Name().Short(), err)
+				return nil
+			}
+			results = append(results, branchResults...)
+			return nil
+		})
+		if err != nil {
+			c.logger.Warn("Failed to iterate references: %v", err)
+		}
+	}
+
+	return results, nil
+}
+
+// ScanOrganization scans every repository matched by pathGlob, the local
+// equivalent of a GitHub org scan, e.g. "/vendor/src/*" to cover a
+// directory of vendored checkouts.
+func (c *Client) ScanOrganization(pathGlob string, branch string, languages []string, safeSpaces []string, deep bool) ([]*types.ScanResult, error) {
+	paths, err := filepath.Glob(pathGlob)
+	if err != nil {
+		return nil, fmt.Errorf("invalid glob %s: %w", pathGlob, err)
+	}
+
+	c.logger.Info("Found %d repositories matching %s", len(paths), pathGlob)
+
+	var allResults []*types.ScanResult
+	for _, path := range paths {
+		if err := c.ctx.Err(); err != nil {
+			return allResults, err
+		}
+
+		repoResults, err := c.ScanRepository(path, branch, languages, safeSpaces, deep)
+		if err != nil {
+			c.logger.Warn("Failed to scan repository %s: %v", path, err)
+			continue
+		}
+		allResults = append(allResults, repoResults...)
+	}
+
+	return allResults, nil
+}
+
+// open resolves target to a *git.Repository: PlainOpen for a local path or
+// file:// URL, PlainClone into a temp directory (cleaned up by the returned
+// func) for an https:// or ssh:// URL.
+func (c *Client) open(target string, branch string) (*git.Repository, func(), error) {
+	noop := func() {}
+
+	if strings.HasPrefix(target, "file://") {
+		target = strings.TrimPrefix(target, "file://")
+	}
+
+	if !strings.HasPrefix(target, "https://") && !strings.HasPrefix(target, "ssh://") {
+		repo, err := git.PlainOpen(target)
+		return repo, noop, err
+	}
+
+	tempDir, err := os.MkdirTemp("", "confused-gitrepo-*")
+	if err != nil {
+		return nil, noop, err
+	}
+	cleanup := func() { os.RemoveAll(tempDir) }
+
+	opts := &git.CloneOptions{URL: target}
+	if branch != "" {
+		opts.ReferenceName = plumbing.NewBranchReferenceName(branch)
+		opts.SingleBranch = true
+	}
+	if c.depth > 0 {
+		opts.Depth = c.depth
+	}
+	if auth := c.netrcAuth(target); auth != nil {
+		opts.Auth = auth
+	}
+
+	repo, err := git.PlainCloneContext(c.ctx, tempDir, false, opts)
+	if err != nil {
+		cleanup()
+		return nil, noop, err
+	}
+	return repo, cleanup, nil
+}
+
+// netrcAuth looks up basic auth credentials for target's host in ~/.netrc
+// (or $NETRC), mirroring how git itself authenticates unauthenticated
+// clone URLs. Returns nil if no netrc file or no matching machine entry is
+// found.
+func (c *Client) netrcAuth(target string) transport.AuthMethod {
+	u, err := url.Parse(target)
+	if err != nil || u.Host == "" {
+		return nil
+	}
+
+	path := os.Getenv("NETRC")
+	if path == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return nil
+		}
+		path = filepath.Join(home, ".netrc")
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+
+	login, password, ok := parseNetrc(string(data), u.Hostname())
+	if !ok {
+		return nil
+	}
+	return &gittransporthttp.BasicAuth{Username: login, Password: password}
+}
+
+// parseNetrc is a minimal netrc reader covering "machine/login/password"
+// triples; it doesn't handle "default" or "macdef" entries.
+func parseNetrc(data string, host string) (login string, password string, ok bool) {
+	fields := strings.Fields(data)
+	matched := false
+	for i := 0; i < len(fields); i++ {
+		switch fields[i] {
+		case "machine":
+			if i+1 < len(fields) {
+				matched = fields[i+1] == host
+			}
+		case "login":
+			if matched && i+1 < len(fields) {
+				login = fields[i+1]
+			}
+		case "password":
+			if matched && i+1 < len(fields) {
+				password = fields[i+1]
+				ok = login != ""
+			}
+		}
+	}
+	return
+}
+
+// scanBranch scans the tree at hash (the tip of branchName) for dependency
+// files.
+func (c *Client) scanBranch(repo *git.Repository, target, branchName string, hash plumbing.Hash, languages []string, safeSpaces []string) ([]*types.ScanResult, error) {
+	var results []*types.ScanResult
+
+	commit, err := repo.CommitObject(hash)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve commit for %s: %w", branchName, err)
+	}
+	tree, err := commit.Tree()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tree for %s: %w", branchName, err)
+	}
+
+	dependencyFiles := c.findDependencyFiles(tree, languages)
+	c.logger.Debug("Found %d dependency files on %s", len(dependencyFiles), branchName)
+
+	for _, file := range dependencyFiles {
+		result, err := c.scanDependencyFile(target, branchName, file, safeSpaces)
+		if err != nil {
+			c.logger.Warn("Failed to scan dependency file %s: %v", file.Name, err)
+			continue
+		}
+		if result != nil {
+			results = append(results, result)
+		}
+	}
+
+	return results, nil
+}
+
+// findDependencyFiles walks tree for blobs matching the dependency file
+// patterns for languages.
+func (c *Client) findDependencyFiles(tree *object.Tree, languages []string) []*object.File {
+	filePatterns := map[string][]string{
+		"npm":      {"package.json", "package-lock.json", "yarn.lock"},
+		"pip":      {"requirements.txt", "requirements-dev.txt", "setup.py", "pyproject.toml"},
+		"composer": {"composer.json", "composer.lock"},
+		"mvn":      {"pom.xml"},
+		"rubygems": {"Gemfile", "Gemfile.lock", "gems.rb"},
+	}
+
+	var allPatterns []string
+	for _, lang := range languages {
+		if patterns, exists := filePatterns[lang]; exists {
+			allPatterns = append(allPatterns, patterns...)
+		}
+	}
+
+	var dependencyFiles []*object.File
+	iter := tree.Files()
+	defer iter.Close()
+	iter.ForEach(func(f *object.File) error {
+		fileName := filepath.Base(f.Name)
+		for _, pattern := range allPatterns {
+			if fileName == pattern {
+				dependencyFiles = append(dependencyFiles, f)
+				break
+			}
+		}
+		return nil
+	})
+
+	return dependencyFiles
+}
+
+// scanDependencyFile scans a single dependency file blob.
+func (c *Client) scanDependencyFile(target, branchName string, file *object.File, safeSpaces []string) (*types.ScanResult, error) {
+	content, err := file.Contents()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read blob content: %w", err)
+	}
+
+	language := c.getLanguageFromFile(file.Name)
+	if language == "" {
+		return nil, fmt.Errorf("unknown language for file: %s", file.Name)
+	}
+
+	result := types.NewScanResult(
+		fmt.Sprintf("%s@%s:%s", target, branchName, file.Name),
+		"gitrepo",
+		language,
+	)
+
+	resolver, err := c.getResolverForLanguage(language)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get resolver for language %s: %w", language, err)
+	}
+
+	tempFile, err := c.createTempFile([]byte(content))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create temporary file: %w", err)
+	}
+	defer os.Remove(tempFile)
+
+	if err := resolver.ReadPackagesFromFile(tempFile); err != nil {
+		return nil, fmt.Errorf("failed to read packages from file: %w", err)
+	}
+
+	if err := resolver.ResolveWithContext(c.ctx); err != nil {
+		return nil, fmt.Errorf("scan cancelled: %w", err)
+	}
+	vulnerablePackages := resolver.PackagesNotInPublic()
+	vulnerablePackages = c.removeSafe(vulnerablePackages, safeSpaces)
+
+	for _, pkg := range vulnerablePackages {
+		result.AddVulnerable(pkg)
+	}
+
+	result.Metadata["file_path"] = file.Name
+	result.Metadata["blob_hash"] = file.Hash.String()
+	result.Metadata["file_size"] = file.Size
+
+	result.Finalize()
+	return result, nil
+}
+
+// getLanguageFromFile determines the language from file path
+func (c *Client) getLanguageFromFile(filePath string) string {
+	fileName := filepath.Base(filePath)
+
+	fileLanguageMap := map[string]string{
+		"package.json":         "npm",
+		"package-lock.json":    "npm",
+		"yarn.lock":            "npm",
+		"requirements.txt":     "pip",
+		"requirements-dev.txt": "pip",
+		"setup.py":             "pip",
+		"pyproject.toml":       "pip",
+		"composer.json":        "composer",
+		"composer.lock":        "composer",
+		"pom.xml":              "mvn",
+		"Gemfile":              "rubygems",
+		"Gemfile.lock":         "rubygems",
+		"gems.rb":              "rubygems",
+	}
+
+	return fileLanguageMap[fileName]
+}
+
+// createTempFile creates a temporary file with the given content
+func (c *Client) createTempFile(content []byte) (string, error) {
+	tempFile, err := os.CreateTemp("", "confused-gitrepo-*.tmp")
+	if err != nil {
+		return "", err
+	}
+
+	if _, err := tempFile.Write(content); err != nil {
+		tempFile.Close()
+		os.Remove(tempFile.Name())
+		return "", err
+	}
+
+	if err := tempFile.Close(); err != nil {
+		os.Remove(tempFile.Name())
+		return "", err
+	}
+
+	return tempFile.Name(), nil
+}
+
+// getResolverForLanguage returns a resolver for the given language
+func (c *Client) getResolverForLanguage(language string) (types.PackageResolver, error) {
+	return resolvers.GetResolverForLanguageWithContext(language, c.config.Verbose, c.ctx, c.limiter, c.registrySet, c.logger, c.config.Workers)
+}
+
+// removeSafe removes known-safe package names from the slice
+func (c *Client) removeSafe(packages []string, safeSpaces []string) []string {
+	if len(safeSpaces) == 0 {
+		return packages
+	}
+
+	retSlice := []string{}
+	for _, pkg := range packages {
+		ignored := false
+		for _, safeSpace := range safeSpaces {
+			ok, err := filepath.Match(safeSpace, pkg)
+			if err != nil {
+				c.logger.Warn("Encountered an error while trying to match a known-safe namespace %s: %v", safeSpace, err)
+				continue
+			}
+			if ok {
+				ignored = true
+				break
+			}
+		}
+		if !ignored {
+			retSlice = append(retSlice, pkg)
+		}
+	}
+	return retSlice
+}
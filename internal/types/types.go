@@ -4,6 +4,9 @@ import (
 	"context"
 	"sync"
 	"time"
+
+	"github.com/h0tak88r/confused/pkg/registries"
+	"golang.org/x/time/rate"
 )
 
 // ScanResult represents the result of a dependency confusion scan
@@ -70,6 +73,11 @@ type PackageResolver interface {
 	PackagesNotInPublic() []string
 	GetPackageCount() int
 	GetLanguage() string
+	// ResolveWithContext re-runs PackagesNotInPublic honoring ctx cancellation
+	// and any rate limiter the resolver was configured with, returning
+	// ctx.Err() as soon as the context is done instead of continuing to hit
+	// the registry.
+	ResolveWithContext(ctx context.Context) error
 }
 
 // EnhancedPackageResolver provides additional functionality for advanced scanning
@@ -100,6 +108,23 @@ type DependencyFile struct {
 	Size     int64
 }
 
+// VCSScanner is implemented by each version-control provider's scanning
+// client (github.Client, gitlab.Client, bitbucket.Client, gitea.Client), so
+// cmd/confused can dispatch a --provider/target-prefix-selected backend
+// without type-switching on a concrete client.
+type VCSScanner interface {
+	// ScanRepository scans a single repository, identified in whatever form
+	// the provider expects (GitHub/Gitea: "owner/repo"; GitLab: numeric ID
+	// or "group/project" path; Bitbucket: "workspace/repo_slug").
+	ScanRepository(repo string, languages []string, safeSpaces []string, deep bool) ([]*ScanResult, error)
+	// ScanOrganization scans every repository under a GitHub org, GitLab
+	// group, Bitbucket workspace, or Gitea org, up to maxRepos.
+	ScanOrganization(org string, languages []string, safeSpaces []string, maxRepos int, deep bool) ([]*ScanResult, error)
+	SetContext(ctx context.Context)
+	SetLimiter(limiter *rate.Limiter)
+	SetRegistries(regs *registries.Set)
+}
+
 // WorkerPool represents a pool of workers for concurrent processing
 type WorkerPool struct {
 	workers    int
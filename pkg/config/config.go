@@ -3,6 +3,7 @@ package config
 import (
 	"time"
 
+	"github.com/h0tak88r/confused/pkg/registries"
 	"github.com/spf13/viper"
 )
 
@@ -11,7 +12,9 @@ type Config struct {
 	// General settings
 	Verbose     bool   `mapstructure:"verbose"`
 	Output      string `mapstructure:"output"`
-	Format      string `mapstructure:"format"` // json, html, text
+	Format      string `mapstructure:"format"` // json, html, text, sarif, spdx
+	LogFormat   string `mapstructure:"log_format"` // text, json, logfmt
+	NoProgress  bool   `mapstructure:"no_progress"`
 	Workers     int    `mapstructure:"workers"`
 	Timeout     int    `mapstructure:"timeout"`
 	
@@ -20,7 +23,21 @@ type Config struct {
 	GitHubOrg   string `mapstructure:"github_org"`
 	GitHubRepo  string `mapstructure:"github_repo"`
 	MaxRepos    int    `mapstructure:"max_repos"`
-	
+
+	// Remediation settings: turn a scan into a closed-loop defense by
+	// filing an issue and/or reserving vulnerable names via a PR. Modes are
+	// "issue", "reserve", "pr" (any combination); RemediationRepo is the
+	// "owner/repo" target for "reserve"/"pr".
+	Remediate       []string `mapstructure:"remediate"`
+	RemediationRepo string   `mapstructure:"remediation_repo"`
+
+	// Cache settings: memoize resolved dependency blobs and registry
+	// availability checks across scans, keyed by tree/blob SHA so an
+	// unchanged file is never re-resolved.
+	CacheDir string `mapstructure:"cache_dir"`
+	CacheTTL int    `mapstructure:"cache_ttl"` // seconds
+	NoCache  bool   `mapstructure:"no_cache"`
+
 	// Target settings
 	Targets     []string `mapstructure:"targets"`
 	TargetFile  string   `mapstructure:"target_file"`
@@ -29,7 +46,17 @@ type Config struct {
 	SafeSpaces  []string `mapstructure:"safe_spaces"`
 	Languages   []string `mapstructure:"languages"`
 	DeepScan    bool     `mapstructure:"deep_scan"`
-	
+
+	// Registry settings: "ecosystem=url" or "ecosystem=url|Header-Name: value"
+	Registries         []string `mapstructure:"registries"`
+	InternalRegistries []string `mapstructure:"internal_registries"`
+
+	// RegistryConfig is the structured alternative to Registries/
+	// InternalRegistries, keyed by ecosystem, for users who want to set a
+	// proxy, skip TLS verification, or override the timeout for one mirror
+	// without reaching for the flag syntax.
+	RegistryConfig map[string]registries.EndpointConfig `mapstructure:"registry_config"`
+
 	// Rate limiting
 	RateLimit   int `mapstructure:"rate_limit"`
 	Delay       int `mapstructure:"delay"`
@@ -43,14 +70,38 @@ type Config struct {
 	FollowRedirects bool `mapstructure:"follow_redirects"`
 	
 	// Database settings (for AutoAR integration)
-	Database struct {
-		Type     string `mapstructure:"type"`
-		Host     string `mapstructure:"host"`
-		Port     int    `mapstructure:"port"`
-		Username string `mapstructure:"username"`
-		Password string `mapstructure:"password"`
-		Database string `mapstructure:"database"`
-	} `mapstructure:"database"`
+	Database DatabaseConfig `mapstructure:"database"`
+
+	// Notifications settings
+	Notifications []NotifierConfig `mapstructure:"notifications"`
+
+	// Providers holds per-VCS-provider settings for the non-GitHub backends
+	// (gitlab, bitbucket, gitea), keyed by provider name.
+	Providers map[string]ProviderConfig `mapstructure:"providers"`
+}
+
+// ProviderConfig configures a single non-GitHub VCS backend.
+type ProviderConfig struct {
+	Token    string `mapstructure:"token"`
+	BaseURL  string `mapstructure:"base_url"` // for self-hosted GitLab/Gitea instances
+	MaxRepos int    `mapstructure:"max_repos"`
+}
+
+// NotifierConfig configures a single enabled pkg/notify plugin.
+type NotifierConfig struct {
+	Type     string            `mapstructure:"type"` // slack, discord, http, email, file
+	Enabled  bool              `mapstructure:"enabled"`
+	Settings map[string]string `mapstructure:"settings"`
+}
+
+// DatabaseConfig holds the settings for the optional scan-history backend.
+type DatabaseConfig struct {
+	Type     string `mapstructure:"type"`
+	Host     string `mapstructure:"host"`
+	Port     int    `mapstructure:"port"`
+	Username string `mapstructure:"username"`
+	Password string `mapstructure:"password"`
+	Database string `mapstructure:"database"`
 }
 
 // Default returns the default configuration
@@ -59,10 +110,11 @@ func Default() *Config {
 		Verbose:         false,
 		Output:          "",
 		Format:          "text",
+		LogFormat:       "text",
 		Workers:         10,
 		Timeout:         30,
 		MaxRepos:        50,
-		Languages:       []string{"npm", "pip", "composer", "mvn", "rubygems"},
+		Languages:       []string{"npm", "pip", "composer", "mvn", "rubygems", "go", "cargo", "nuget"},
 		DeepScan:        false,
 		RateLimit:       100,
 		Delay:           100,
@@ -71,29 +123,40 @@ func Default() *Config {
 		UserAgent:       "Confused-DepConfusion-Scanner/2.0",
 		FollowRedirects: true,
 		SafeSpaces:      []string{},
+		CacheDir:        "./.confused-cache",
+		CacheTTL:        3600,
 	}
 }
 
-// Load loads configuration from files and environment variables
-func Load(cfg *Config) {
+// Load loads configuration from files and environment variables. configFile,
+// when non-empty (the --config flag), is read verbatim instead of the usual
+// ./confused.yaml / $HOME/.confused / /etc/confused search path.
+func Load(cfg *Config, configFile string) {
 	// Store CLI flag values before they get overwritten
 	cliGitHubToken := cfg.GitHubToken
 	cliVerbose := cfg.Verbose
 	cliOutput := cfg.Output
 	cliFormat := cfg.Format
+	cliLogFormat := cfg.LogFormat
 	cliWorkers := cfg.Workers
 	cliTimeout := cfg.Timeout
 	cliSafeSpaces := cfg.SafeSpaces
+	cliRegistries := cfg.Registries
+	cliInternalRegistries := cfg.InternalRegistries
 	cliOutputDir := cfg.OutputDir
 	cliSaveResults := cfg.SaveResults
 	cliUserAgent := cfg.UserAgent
 
 	// Set config file
-	viper.SetConfigName("confused")
-	viper.SetConfigType("yaml")
-	viper.AddConfigPath(".")
-	viper.AddConfigPath("$HOME/.confused")
-	viper.AddConfigPath("/etc/confused")
+	if configFile != "" {
+		viper.SetConfigFile(configFile)
+	} else {
+		viper.SetConfigName("confused")
+		viper.SetConfigType("yaml")
+		viper.AddConfigPath(".")
+		viper.AddConfigPath("$HOME/.confused")
+		viper.AddConfigPath("/etc/confused")
+	}
 
 	// Set environment variables
 	viper.AutomaticEnv()
@@ -126,6 +189,9 @@ func Load(cfg *Config) {
 	if cliFormat != "" {
 		cfg.Format = cliFormat
 	}
+	if cliLogFormat != "" {
+		cfg.LogFormat = cliLogFormat
+	}
 	if cliWorkers > 0 {
 		cfg.Workers = cliWorkers
 	}
@@ -135,6 +201,12 @@ func Load(cfg *Config) {
 	if len(cliSafeSpaces) > 0 {
 		cfg.SafeSpaces = cliSafeSpaces
 	}
+	if len(cliRegistries) > 0 {
+		cfg.Registries = cliRegistries
+	}
+	if len(cliInternalRegistries) > 0 {
+		cfg.InternalRegistries = cliInternalRegistries
+	}
 	if cliOutputDir != "" {
 		cfg.OutputDir = cliOutputDir
 	}
@@ -153,3 +225,8 @@ func (c *Config) GetTimeout() time.Duration {
 func (c *Config) GetDelay() time.Duration {
 	return time.Duration(c.Delay) * time.Millisecond
 }
+
+// GetCacheTTL returns the cache TTL as a duration
+func (c *Config) GetCacheTTL() time.Duration {
+	return time.Duration(c.CacheTTL) * time.Second
+}
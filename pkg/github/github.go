@@ -6,29 +6,39 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/google/go-github/v58/github"
+	"github.com/h0tak88r/confused/internal/cache"
 	"github.com/h0tak88r/confused/internal/resolvers"
 	"github.com/h0tak88r/confused/internal/types"
 	"github.com/h0tak88r/confused/pkg/config"
 	"github.com/h0tak88r/confused/pkg/logger"
+	"github.com/h0tak88r/confused/pkg/progress"
+	"github.com/h0tak88r/confused/pkg/registries"
 	"golang.org/x/oauth2"
+	"golang.org/x/time/rate"
 )
 
 // Client represents a GitHub API client
 type Client struct {
-	client *github.Client
-	ctx    context.Context
-	config *config.Config
-	logger *logger.Logger
+	client      *github.Client
+	ctx         context.Context
+	config      *config.Config
+	logger      *logger.Logger
+	progress    progress.Progress
+	limiter     *rate.Limiter
+	registrySet *registries.Set
+	cache       cache.Store
+	cacheTTL    time.Duration
 }
 
 // New creates a new GitHub client
 func New(cfg *config.Config, log *logger.Logger) (*Client, error) {
 	ctx := context.Background()
-	
+
 	var client *github.Client
-	
+
 	if cfg.GitHubToken != "" {
 		log.Debug("Using GitHub token for authentication")
 		log.Info("GitHub token provided - using authenticated requests")
@@ -44,13 +54,53 @@ func New(cfg *config.Config, log *logger.Logger) (*Client, error) {
 	}
 
 	return &Client{
-		client: client,
-		ctx:    ctx,
-		config: cfg,
-		logger: log,
+		client:   client,
+		ctx:      ctx,
+		config:   cfg,
+		logger:   log,
+		progress: &progress.NoopProgress{},
 	}, nil
 }
 
+// SetProgress attaches a progress reporter that ScanOrganization advances as
+// each repository finishes, instead of scanning hundreds of repos silently.
+func (gc *Client) SetProgress(p progress.Progress) {
+	gc.progress = p
+}
+
+// SetContext points the client at a cancellable root context, so SIGINT/
+// SIGTERM during an org scan stops in-flight repository and registry calls
+// instead of running to completion.
+func (gc *Client) SetContext(ctx context.Context) {
+	gc.ctx = ctx
+}
+
+// SetLimiter points this client's resolvers at a shared rate limiter.
+func (gc *Client) SetLimiter(limiter *rate.Limiter) {
+	gc.limiter = limiter
+}
+
+// SetRegistries points this client's resolvers at the configured
+// public/internal registry mirrors.
+func (gc *Client) SetRegistries(regs *registries.Set) {
+	gc.registrySet = regs
+}
+
+// SetCache points this client at the incremental scan cache, so a
+// dependency blob already resolved in a prior branch/repo isn't
+// downloaded and re-resolved again.
+func (gc *Client) SetCache(store cache.Store, ttl time.Duration) {
+	gc.cache = store
+	gc.cacheTTL = ttl
+}
+
+// RawClient returns the underlying, already-authenticated go-github client,
+// so subsystems like internal/remediation can make GitHub API calls
+// pkg/github doesn't itself wrap without re-deriving OAuth token setup.
+func (gc *Client) RawClient() *github.Client {
+	return gc.client
+}
+
 // ScanRepository scans a specific GitHub repository
 func (gc *Client) ScanRepository(repo string, languages []string, safeSpaces []string, deep bool) ([]*types.ScanResult, error) {
 	// Parse repository name
@@ -120,36 +170,49 @@ func (gc *Client) ScanOrganization(org string, languages []string, safeSpaces []
 	}
 	
 	gc.logger.Info("Found %d repositories", len(repos))
-	
+
+	gc.progress.Start(len(repos))
+	defer gc.progress.Finish()
+
 	// Create worker pool
 	workerPool := types.NewWorkerPool(gc.config.Workers)
 	workerPool.Start()
 	defer workerPool.Stop()
-	
+
 	// Results channel
 	resultsChan := make(chan []*types.ScanResult, len(repos))
 	var allResults []*types.ScanResult
-	
+
 	// Submit jobs
 	for _, repo := range repos {
+		if err := gc.ctx.Err(); err != nil {
+			return allResults, err
+		}
 		repo := repo // Capture for closure
 		workerPool.Submit(func() {
 			repoResults, err := gc.ScanRepository(repo.GetFullName(), languages, safeSpaces, deep)
 			if err != nil {
 				gc.logger.Warn("Failed to scan repository %s: %v", repo.GetFullName(), err)
+				gc.progress.Advance(repo.GetFullName())
 				resultsChan <- []*types.ScanResult{}
 				return
 			}
+			if counter, ok := gc.progress.(progress.CountingProgress); ok {
+				for _, result := range repoResults {
+					counter.AddCounts(len(result.Vulnerable), len(result.Safe))
+				}
+			}
+			gc.progress.Advance(repo.GetFullName())
 			resultsChan <- repoResults
 		})
 	}
-	
+
 	// Collect results
 	for i := 0; i < len(repos); i++ {
 		repoResults := <-resultsChan
 		allResults = append(allResults, repoResults...)
 	}
-	
+
 	return allResults, nil
 }
 
@@ -239,7 +302,7 @@ func (gc *Client) scanBranch(owner, repo, branch string, languages []string, saf
 	
 	// Scan each dependency file
 	for _, file := range dependencyFiles {
-		result, err := gc.scanDependencyFile(owner, repo, file, safeSpaces)
+		result, err := gc.scanDependencyFile(owner, repo, branch, file, safeSpaces)
 		if err != nil {
 			gc.logger.Warn("Failed to scan dependency file %s: %v", file.GetPath(), err)
 			continue
@@ -264,6 +327,9 @@ func (gc *Client) findDependencyFiles(entries []*github.TreeEntry, languages []s
 		"composer": {"composer.json", "composer.lock"},
 		"mvn":      {"pom.xml"},
 		"rubygems": {"Gemfile", "Gemfile.lock", "gems.rb"},
+		"go":       {"go.mod", "go.sum"},
+		"cargo":    {"Cargo.toml", "Cargo.lock"},
+		"nuget":    {"packages.config"},
 	}
 	
 	// Collect all patterns for the requested languages
@@ -291,64 +357,96 @@ func (gc *Client) findDependencyFiles(entries []*github.TreeEntry, languages []s
 }
 
 // scanDependencyFile scans a specific dependency file
-func (gc *Client) scanDependencyFile(owner, repo string, file *github.TreeEntry, safeSpaces []string) (*types.ScanResult, error) {
-	// Get file content
-	content, err := gc.getFileContent(owner, repo, file.GetSHA())
-	if err != nil {
-		return nil, fmt.Errorf("failed to get file content: %w", err)
-	}
-	
+func (gc *Client) scanDependencyFile(owner, repo, branch string, file *github.TreeEntry, safeSpaces []string) (*types.ScanResult, error) {
 	// Determine language from file extension
 	language := gc.getLanguageFromFile(file.GetPath())
 	if language == "" {
 		return nil, fmt.Errorf("unknown language for file: %s", file.GetPath())
 	}
-	
+
 	// Create scan result
 	result := types.NewScanResult(
 		fmt.Sprintf("%s/%s:%s", owner, repo, file.GetPath()),
 		"github",
 		language,
 	)
-	
+
+	vulnerablePackages, err := gc.resolveBlob(owner, repo, language, file)
+	if err != nil {
+		return nil, err
+	}
+
+	// Remove safe spaces. Applied after the blob cache lookup/fill so a
+	// cached entry reflects the blob's intrinsic registry state, not one
+	// particular scan's --safe-spaces value.
+	vulnerablePackages = gc.removeSafe(vulnerablePackages, safeSpaces)
+
+	// Add to result
+	for _, pkg := range vulnerablePackages {
+		result.AddVulnerable(pkg)
+	}
+
+	// Add metadata
+	result.Metadata["file_path"] = file.GetPath()
+	result.Metadata["file_sha"] = file.GetSHA()
+	result.Metadata["file_size"] = file.GetSize()
+	result.Metadata["branch"] = branch
+
+	// Finalize result
+	result.Finalize()
+
+	return result, nil
+}
+
+// resolveBlob returns the vulnerable package names for file's blob,
+// consulting the incremental scan cache (keyed by provider/language/blob
+// SHA) before downloading and resolving its content. A cache hit means an
+// unchanged dependency file - even one renamed, moved, or vendored into
+// another repo - is never re-downloaded or re-resolved.
+func (gc *Client) resolveBlob(owner, repo, language string, file *github.TreeEntry) ([]string, error) {
+	if gc.cache != nil {
+		if entry, ok := cache.GetBlob(gc.cache, "github", language, file.GetSHA()); ok {
+			return entry.Vulnerable, nil
+		}
+	}
+
+	// Get file content
+	content, err := gc.getFileContent(owner, repo, file.GetSHA())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get file content: %w", err)
+	}
+
 	// Get resolver for the language
 	resolver, err := gc.getResolverForLanguage(language)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get resolver for language %s: %w", language, err)
 	}
-	
+
 	// Create temporary file
 	tempFile, err := gc.createTempFile(content)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create temporary file: %w", err)
 	}
 	defer os.Remove(tempFile)
-	
+
 	// Read packages from file
 	if err := resolver.ReadPackagesFromFile(tempFile); err != nil {
 		return nil, fmt.Errorf("failed to read packages from file: %w", err)
 	}
-	
-	// Get vulnerable packages
+
+	// Get vulnerable packages, aborting early if the context was cancelled
+	if err := resolver.ResolveWithContext(gc.ctx); err != nil {
+		return nil, fmt.Errorf("scan cancelled: %w", err)
+	}
 	vulnerablePackages := resolver.PackagesNotInPublic()
-	
-	// Remove safe spaces
-	vulnerablePackages = gc.removeSafe(vulnerablePackages, safeSpaces)
-	
-	// Add to result
-	for _, pkg := range vulnerablePackages {
-		result.AddVulnerable(pkg)
+
+	if gc.cache != nil {
+		if err := cache.SetBlob(gc.cache, "github", language, file.GetSHA(), &cache.BlobEntry{Vulnerable: vulnerablePackages}); err != nil {
+			gc.logger.Warn("Failed to cache resolved blob %s: %v", file.GetSHA(), err)
+		}
 	}
-	
-	// Add metadata
-	result.Metadata["file_path"] = file.GetPath()
-	result.Metadata["file_sha"] = file.GetSHA()
-	result.Metadata["file_size"] = file.GetSize()
-	
-	// Finalize result
-	result.Finalize()
-	
-	return result, nil
+
+	return vulnerablePackages, nil
 }
 
 // getFileContent gets the content of a file from GitHub
@@ -414,7 +512,7 @@ func (gc *Client) createTempFile(content []byte) (string, error) {
 
 // getResolverForLanguage returns a resolver for the given language
 func (gc *Client) getResolverForLanguage(language string) (types.PackageResolver, error) {
-	return resolvers.GetResolverForLanguageWithVerbose(language, gc.config.Verbose)
+	return resolvers.GetResolverForLanguageWithCache(language, gc.config.Verbose, gc.ctx, gc.limiter, gc.registrySet, gc.logger, gc.config.Workers, gc.cache, gc.cacheTTL)
 }
 
 // removeSafe removes known-safe package names from the slice
@@ -0,0 +1,227 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// HookEntry is the leveled, field-carrying record passed to a Hook's Fire
+// method - a level-agnostic view of a single log line.
+type HookEntry struct {
+	Time    time.Time
+	Level   LogLevel
+	Message string
+	Fields  map[string]interface{}
+}
+
+// Hook receives every log record at or above the level(s) it was registered
+// for via AddHook, e.g. to rotate log files or ship errors to a webhook.
+type Hook interface {
+	Fire(entry *HookEntry) error
+}
+
+// AddHook registers h to run on every record whose level is one of levels.
+// With no levels given, h runs on every record regardless of level.
+func (l *Logger) AddHook(h Hook, levels ...LogLevel) {
+	l.entry.AddHook(&logrusHookAdapter{hook: h, levels: toLogrusLevels(levels)})
+}
+
+// logrusHookAdapter lets our Hook interface plug into logrus' own hook
+// mechanism without exposing logrus types on Hook itself.
+type logrusHookAdapter struct {
+	hook   Hook
+	levels []logrus.Level
+}
+
+func (a *logrusHookAdapter) Levels() []logrus.Level {
+	if len(a.levels) == 0 {
+		return logrus.AllLevels
+	}
+	return a.levels
+}
+
+func (a *logrusHookAdapter) Fire(e *logrus.Entry) error {
+	return a.hook.Fire(&HookEntry{
+		Time:    e.Time,
+		Level:   fromLogrusLevel(e.Level),
+		Message: e.Message,
+		Fields:  map[string]interface{}(e.Data),
+	})
+}
+
+func toLogrusLevels(levels []LogLevel) []logrus.Level {
+	out := make([]logrus.Level, 0, len(levels))
+	for _, lvl := range levels {
+		out = append(out, toLogrusLevel(lvl))
+	}
+	return out
+}
+
+func fromLogrusLevel(level logrus.Level) LogLevel {
+	switch level {
+	case logrus.DebugLevel, logrus.TraceLevel:
+		return DEBUG
+	case logrus.InfoLevel:
+		return INFO
+	case logrus.WarnLevel:
+		return WARN
+	case logrus.ErrorLevel:
+		return ERROR
+	default:
+		return FATAL
+	}
+}
+
+// RotatingFileHook writes every record it receives to a log file, rotating
+// it to a timestamped sibling once it exceeds maxSizeBytes or once the
+// calendar day changes (whichever comes first). A maxSizeBytes of 0 disables
+// the size-based trigger.
+type RotatingFileHook struct {
+	path         string
+	maxSizeBytes int64
+	rotateDaily  bool
+
+	mu      sync.Mutex
+	file    *os.File
+	size    int64
+	openDay int
+}
+
+// NewRotatingFileHook opens (creating if needed) path for appending and
+// returns a hook that rotates it per maxSizeBytes/rotateDaily.
+func NewRotatingFileHook(path string, maxSizeBytes int64, rotateDaily bool) (*RotatingFileHook, error) {
+	h := &RotatingFileHook{path: path, maxSizeBytes: maxSizeBytes, rotateDaily: rotateDaily}
+	if err := h.openCurrent(); err != nil {
+		return nil, err
+	}
+	return h, nil
+}
+
+func (h *RotatingFileHook) openCurrent() error {
+	if dir := filepath.Dir(h.path); dir != "." {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return fmt.Errorf("failed to create log directory: %w", err)
+		}
+	}
+	file, err := os.OpenFile(h.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0666)
+	if err != nil {
+		return fmt.Errorf("failed to open log file: %w", err)
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return err
+	}
+	h.file = file
+	h.size = info.Size()
+	h.openDay = time.Now().YearDay()
+	return nil
+}
+
+func (h *RotatingFileHook) rotate() error {
+	if err := h.file.Close(); err != nil {
+		return err
+	}
+	rotated := fmt.Sprintf("%s.%s", h.path, time.Now().Format("20060102-150405"))
+	if err := os.Rename(h.path, rotated); err != nil {
+		return err
+	}
+	return h.openCurrent()
+}
+
+// Fire appends entry to the current file, rotating first if needed.
+func (h *RotatingFileHook) Fire(entry *HookEntry) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if (h.maxSizeBytes > 0 && h.size >= h.maxSizeBytes) || (h.rotateDaily && entry.Time.YearDay() != h.openDay) {
+		if err := h.rotate(); err != nil {
+			return err
+		}
+	}
+
+	line := formatLogfmtLine(entry)
+	n, err := h.file.WriteString(line)
+	if err != nil {
+		return err
+	}
+	h.size += int64(n)
+	return nil
+}
+
+// Close closes the hook's underlying file.
+func (h *RotatingFileHook) Close() error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.file.Close()
+}
+
+func formatLogfmtLine(entry *HookEntry) string {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "time=%q level=%s msg=%q", entry.Time.Format(time.RFC3339), levelString(entry.Level), entry.Message)
+	for k, v := range entry.Fields {
+		fmt.Fprintf(&b, " %s=%v", k, v)
+	}
+	b.WriteByte('\n')
+	return b.String()
+}
+
+func levelString(level LogLevel) string {
+	switch level {
+	case DEBUG:
+		return "debug"
+	case INFO:
+		return "info"
+	case WARN:
+		return "warn"
+	case ERROR:
+		return "error"
+	default:
+		return "fatal"
+	}
+}
+
+// WebhookHook POSTs a JSON-encoded HookEntry to url for every record it
+// fires on - typically registered with AddHook(hook, logger.ERROR,
+// logger.FATAL) so only failures get shipped, not routine scan progress.
+type WebhookHook struct {
+	url    string
+	client *http.Client
+}
+
+// NewWebhookHook returns a hook that POSTs to url using an HTTP client with
+// the given timeout.
+func NewWebhookHook(url string, timeout time.Duration) *WebhookHook {
+	return &WebhookHook{url: url, client: &http.Client{Timeout: timeout}}
+}
+
+// Fire POSTs entry to the configured webhook URL as JSON.
+func (w *WebhookHook) Fire(entry *HookEntry) error {
+	payload, err := json.Marshal(map[string]interface{}{
+		"time":    entry.Time.Format(time.RFC3339),
+		"level":   levelString(entry.Level),
+		"message": entry.Message,
+		"fields":  entry.Fields,
+	})
+	if err != nil {
+		return err
+	}
+
+	resp, err := w.client.Post(w.url, "application/json", bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook %s returned status %s", w.url, resp.Status)
+	}
+	return nil
+}
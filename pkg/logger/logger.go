@@ -5,9 +5,8 @@ import (
 	"io"
 	"os"
 	"path/filepath"
-	"time"
 
-	"github.com/fatih/color"
+	"github.com/sirupsen/logrus"
 )
 
 // LogLevel represents the logging level
@@ -21,9 +20,22 @@ const (
 	FATAL
 )
 
-// Logger represents a structured logger
+// Format selects how log records are rendered.
+type Format string
+
+const (
+	// FormatText renders human-friendly, colored lines (the default).
+	FormatText Format = "text"
+	// FormatJSON renders one JSON object per line, for ELK/Loki/Datadog ingestion.
+	FormatJSON Format = "json"
+	// FormatLogfmt renders one key=value line per record, for tools (Loki,
+	// Vector, journald) that parse logfmt rather than JSON.
+	FormatLogfmt Format = "logfmt"
+)
+
+// Logger represents a structured logger built on logrus
 type Logger struct {
-	level    LogLevel
+	entry    *logrus.Logger
 	verbose  bool
 	file     *os.File
 	filePath string
@@ -31,8 +43,14 @@ type Logger struct {
 
 // New creates a new logger instance
 func New(level LogLevel, verbose bool, logFile string) (*Logger, error) {
+	entry := logrus.New()
+	entry.SetFormatter(&logrus.TextFormatter{
+		FullTimestamp: true,
+	})
+	entry.SetLevel(toLogrusLevel(level))
+
 	logger := &Logger{
-		level:   level,
+		entry:   entry,
 		verbose: verbose,
 	}
 
@@ -50,6 +68,7 @@ func New(level LogLevel, verbose bool, logFile string) (*Logger, error) {
 
 		logger.file = file
 		logger.filePath = logFile
+		entry.SetOutput(io.MultiWriter(os.Stdout, file))
 	}
 
 	return logger, nil
@@ -63,99 +82,64 @@ func (l *Logger) Close() error {
 	return nil
 }
 
-// log writes a log message with the given level
-func (l *Logger) log(level LogLevel, format string, args ...interface{}) {
-	if level < l.level {
-		return
-	}
-
-	timestamp := time.Now().Format("2006-01-02 15:04:05")
-	levelStr := l.getLevelString(level)
-	message := fmt.Sprintf(format, args...)
-	
-	logMessage := fmt.Sprintf("[%s] [%s] %s", timestamp, levelStr, message)
-
-	// Write to console with colors
-	l.writeToConsole(level, logMessage)
-
-	// Write to file if available
-	if l.file != nil {
-		fmt.Fprintln(l.file, logMessage)
-	}
-}
-
-// writeToConsole writes the message to console with appropriate colors
-func (l *Logger) writeToConsole(level LogLevel, message string) {
-	switch level {
-	case DEBUG:
-		if l.verbose {
-			color.New(color.FgCyan).Println(message)
-		}
-	case INFO:
-		color.New(color.FgGreen).Println(message)
-	case WARN:
-		color.New(color.FgYellow).Println(message)
-	case ERROR:
-		color.New(color.FgRed).Println(message)
-	case FATAL:
-		color.New(color.FgRed, color.Bold).Println(message)
+// SetFormat switches the logger between human-friendly text and one-JSON-object-per-line
+// output, selected via the --log-format flag.
+func (l *Logger) SetFormat(format Format) {
+	switch format {
+	case FormatJSON:
+		l.entry.SetFormatter(&logrus.JSONFormatter{})
+	case FormatLogfmt:
+		l.entry.SetFormatter(&logrus.TextFormatter{DisableColors: true, FullTimestamp: true})
 	default:
-		fmt.Println(message)
+		l.entry.SetFormatter(&logrus.TextFormatter{FullTimestamp: true})
 	}
 }
 
-// getLevelString returns the string representation of the log level
-func (l *Logger) getLevelString(level LogLevel) string {
-	switch level {
-	case DEBUG:
-		return "DEBUG"
-	case INFO:
-		return "INFO"
-	case WARN:
-		return "WARN"
-	case ERROR:
-		return "ERROR"
-	case FATAL:
-		return "FATAL"
-	default:
-		return "UNKNOWN"
-	}
+// WithFields returns an Entry that carries the given structured fields
+// (e.g. target, language, repo, worker_id) on every subsequent log call.
+func (l *Logger) WithFields(fields map[string]interface{}) *Entry {
+	return &Entry{entry: l.entry.WithFields(logrus.Fields(fields))}
 }
 
 // Debug logs a debug message
 func (l *Logger) Debug(format string, args ...interface{}) {
-	l.log(DEBUG, format, args...)
+	if l.verbose {
+		l.entry.Debugf(format, args...)
+	}
 }
 
 // Info logs an info message
 func (l *Logger) Info(format string, args ...interface{}) {
-	l.log(INFO, format, args...)
+	l.entry.Infof(format, args...)
 }
 
 // Warn logs a warning message
 func (l *Logger) Warn(format string, args ...interface{}) {
-	l.log(WARN, format, args...)
+	l.entry.Warnf(format, args...)
 }
 
 // Error logs an error message
 func (l *Logger) Error(format string, args ...interface{}) {
-	l.log(ERROR, format, args...)
+	l.entry.Errorf(format, args...)
 }
 
 // Fatal logs a fatal message and exits
 func (l *Logger) Fatal(format string, args ...interface{}) {
-	l.log(FATAL, format, args...)
+	l.entry.Errorf(format, args...)
 	os.Exit(1)
 }
 
 // SetLevel sets the logging level
 func (l *Logger) SetLevel(level LogLevel) {
-	l.level = level
+	l.entry.SetLevel(toLogrusLevel(level))
 }
 
 // SetVerbose sets the verbose mode
 func (l *Logger) SetVerbose(verbose bool) {
 	l.verbose = verbose
+	if verbose {
+		l.entry.SetLevel(logrus.DebugLevel)
+	}
 }
 
 // GetFileWriter returns a writer for the log file
@@ -165,3 +149,51 @@ func (l *Logger) GetFileWriter() io.Writer {
 	}
 	return os.Stdout
 }
+
+// Entry is a Logger bound to a fixed set of structured fields, returned by WithFields.
+type Entry struct {
+	entry *logrus.Entry
+}
+
+// WithFields returns a new Entry with additional fields merged into this one's.
+func (e *Entry) WithFields(fields map[string]interface{}) *Entry {
+	return &Entry{entry: e.entry.WithFields(logrus.Fields(fields))}
+}
+
+// Debug logs a debug message with the entry's fields attached
+func (e *Entry) Debug(format string, args ...interface{}) {
+	e.entry.Debugf(format, args...)
+}
+
+// Info logs an info message with the entry's fields attached
+func (e *Entry) Info(format string, args ...interface{}) {
+	e.entry.Infof(format, args...)
+}
+
+// Warn logs a warning message with the entry's fields attached
+func (e *Entry) Warn(format string, args ...interface{}) {
+	e.entry.Warnf(format, args...)
+}
+
+// Error logs an error message with the entry's fields attached
+func (e *Entry) Error(format string, args ...interface{}) {
+	e.entry.Errorf(format, args...)
+}
+
+// toLogrusLevel maps our LogLevel to logrus' level scale
+func toLogrusLevel(level LogLevel) logrus.Level {
+	switch level {
+	case DEBUG:
+		return logrus.DebugLevel
+	case INFO:
+		return logrus.InfoLevel
+	case WARN:
+		return logrus.WarnLevel
+	case ERROR:
+		return logrus.ErrorLevel
+	case FATAL:
+		return logrus.FatalLevel
+	default:
+		return logrus.InfoLevel
+	}
+}
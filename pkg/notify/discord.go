@@ -0,0 +1,51 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/h0tak88r/confused/internal/types"
+)
+
+// DiscordNotifier posts vulnerable findings to a Discord webhook.
+type DiscordNotifier struct {
+	webhookURL string
+	client     *http.Client
+}
+
+// NewDiscordNotifier constructs a DiscordNotifier posting to webhookURL.
+func NewDiscordNotifier(webhookURL string) *DiscordNotifier {
+	return &DiscordNotifier{webhookURL: webhookURL, client: &http.Client{}}
+}
+
+// Notify implements Notifier.
+func (d *DiscordNotifier) Notify(ctx context.Context, result *types.ScanResult) error {
+	content := fmt.Sprintf("⚠️ Dependency confusion risk in **%s** (%s): %s",
+		result.Target, result.Language, strings.Join(result.Vulnerable, ", "))
+
+	body, err := json.Marshal(map[string]string{"content": content})
+	if err != nil {
+		return fmt.Errorf("failed to marshal discord payload: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", d.webhookURL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to build discord request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to post to discord webhook: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("discord webhook returned status %d", resp.StatusCode)
+	}
+	return nil
+}
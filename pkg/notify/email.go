@@ -0,0 +1,64 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+	"net/smtp"
+	"strings"
+
+	"github.com/h0tak88r/confused/internal/types"
+)
+
+// EmailNotifier emails vulnerable findings through an SMTP relay.
+type EmailNotifier struct {
+	host     string
+	port     string
+	username string
+	password string
+	from     string
+	to       []string
+}
+
+// NewEmailNotifier constructs an EmailNotifier from the notifier's settings
+// map (host, port, username, password, from, to — "to" is comma-separated).
+func NewEmailNotifier(settings map[string]string) *EmailNotifier {
+	var to []string
+	if raw := settings["to"]; raw != "" {
+		for _, addr := range strings.Split(raw, ",") {
+			to = append(to, strings.TrimSpace(addr))
+		}
+	}
+
+	return &EmailNotifier{
+		host:     settings["host"],
+		port:     settings["port"],
+		username: settings["username"],
+		password: settings["password"],
+		from:     settings["from"],
+		to:       to,
+	}
+}
+
+// Notify implements Notifier. SMTP has no context-cancellable API in the
+// standard library, so ctx is only consulted before dialing.
+func (e *EmailNotifier) Notify(ctx context.Context, result *types.ScanResult) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if len(e.to) == 0 {
+		return fmt.Errorf("email notifier has no recipients configured")
+	}
+
+	subject := fmt.Sprintf("Subject: [confused] dependency confusion risk in %s\r\n", result.Target)
+	body := fmt.Sprintf("The following packages in %s (%s) were not found in the public registry:\r\n\r\n- %s\r\n",
+		result.Target, result.Language, strings.Join(result.Vulnerable, "\r\n- "))
+	message := []byte(subject + "\r\n" + body)
+
+	auth := smtp.PlainAuth("", e.username, e.password, e.host)
+	addr := fmt.Sprintf("%s:%s", e.host, e.port)
+
+	if err := smtp.SendMail(addr, auth, e.from, e.to, message); err != nil {
+		return fmt.Errorf("failed to send notification email: %w", err)
+	}
+	return nil
+}
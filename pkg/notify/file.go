@@ -0,0 +1,44 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/h0tak88r/confused/internal/types"
+)
+
+// FileNotifier appends one NDJSON line per vulnerable result to a local file,
+// for operators who want to `tail -f | jq` scan findings.
+type FileNotifier struct {
+	path string
+}
+
+// NewFileNotifier constructs a FileNotifier appending to path.
+func NewFileNotifier(path string) *FileNotifier {
+	return &FileNotifier{path: path}
+}
+
+// Notify implements Notifier.
+func (f *FileNotifier) Notify(ctx context.Context, result *types.ScanResult) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	line, err := json.Marshal(result)
+	if err != nil {
+		return fmt.Errorf("failed to marshal scan result: %w", err)
+	}
+
+	file, err := os.OpenFile(f.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open notification file %s: %w", f.path, err)
+	}
+	defer file.Close()
+
+	if _, err := file.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("failed to append to notification file %s: %w", f.path, err)
+	}
+	return nil
+}
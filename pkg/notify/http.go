@@ -0,0 +1,49 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/h0tak88r/confused/internal/types"
+)
+
+// HTTPNotifier POSTs the raw scan result as JSON to a generic endpoint, for
+// integrations (ticketing systems, internal dashboards) that aren't Slack or
+// Discord.
+type HTTPNotifier struct {
+	url    string
+	client *http.Client
+}
+
+// NewHTTPNotifier constructs an HTTPNotifier posting to url.
+func NewHTTPNotifier(url string) *HTTPNotifier {
+	return &HTTPNotifier{url: url, client: &http.Client{}}
+}
+
+// Notify implements Notifier.
+func (h *HTTPNotifier) Notify(ctx context.Context, result *types.ScanResult) error {
+	body, err := json.Marshal(result)
+	if err != nil {
+		return fmt.Errorf("failed to marshal scan result: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", h.url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to build http request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := h.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to post scan result: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("notification endpoint returned status %d", resp.StatusCode)
+	}
+	return nil
+}
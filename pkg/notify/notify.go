@@ -0,0 +1,105 @@
+// Package notify fans vulnerable scan findings out to chat/ticketing/audit-log
+// destinations (Slack, Discord, generic HTTP, email, a local NDJSON file)
+// without hard-coding any one integration into the scan command runners.
+package notify
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/h0tak88r/confused/internal/types"
+	"github.com/h0tak88r/confused/pkg/config"
+	"github.com/h0tak88r/confused/pkg/logger"
+)
+
+// Notifier delivers a vulnerable scan result to a single destination.
+type Notifier interface {
+	Notify(ctx context.Context, result *types.ScanResult) error
+}
+
+// maxRetries bounds the retry-with-backoff loop each notifier gets in Dispatch.
+const maxRetries = 3
+
+// perNotifierTimeout bounds how long a single notifier may take before it is
+// considered failed for this attempt.
+const perNotifierTimeout = 10 * time.Second
+
+// Build constructs the Notifier set described by cfg.Notifications, skipping
+// any entries with Enabled == false.
+func Build(cfg []config.NotifierConfig, log *logger.Logger) ([]Notifier, error) {
+	var notifiers []Notifier
+	for _, nc := range cfg {
+		if !nc.Enabled {
+			continue
+		}
+		notifier, err := forType(nc)
+		if err != nil {
+			return nil, fmt.Errorf("failed to configure %s notifier: %w", nc.Type, err)
+		}
+		notifiers = append(notifiers, notifier)
+	}
+	return notifiers, nil
+}
+
+// forType returns the Notifier implementation for a single NotifierConfig.
+func forType(nc config.NotifierConfig) (Notifier, error) {
+	switch nc.Type {
+	case "slack":
+		return NewSlackNotifier(nc.Settings["webhook_url"]), nil
+	case "discord":
+		return NewDiscordNotifier(nc.Settings["webhook_url"]), nil
+	case "http":
+		return NewHTTPNotifier(nc.Settings["url"]), nil
+	case "email":
+		return NewEmailNotifier(nc.Settings), nil
+	case "file":
+		return NewFileNotifier(nc.Settings["path"]), nil
+	default:
+		return nil, fmt.Errorf("unsupported notifier type: %s", nc.Type)
+	}
+}
+
+// Dispatch fans result out to every notifier concurrently, applying a
+// per-notifier timeout and retry-with-backoff. Failures are logged, not
+// returned, so one broken notifier never blocks the others or the scan.
+func Dispatch(ctx context.Context, notifiers []Notifier, result *types.ScanResult, log *logger.Logger) {
+	if !result.IsVulnerable() || len(notifiers) == 0 {
+		return
+	}
+
+	done := make(chan struct{}, len(notifiers))
+	for _, notifier := range notifiers {
+		notifier := notifier
+		go func() {
+			defer func() { done <- struct{}{} }()
+			if err := notifyWithRetry(ctx, notifier, result); err != nil {
+				log.Warn("Notifier failed after retries: %v", err)
+			}
+		}()
+	}
+
+	for range notifiers {
+		<-done
+	}
+}
+
+// notifyWithRetry calls notifier.Notify, retrying with exponential backoff
+// and bounding each attempt by perNotifierTimeout.
+func notifyWithRetry(ctx context.Context, notifier Notifier, result *types.ScanResult) error {
+	var lastErr error
+	for attempt := 0; attempt <= maxRetries; attempt++ {
+		attemptCtx, cancel := context.WithTimeout(ctx, perNotifierTimeout)
+		err := notifier.Notify(attemptCtx, result)
+		cancel()
+		if err == nil {
+			return nil
+		}
+		lastErr = err
+
+		if attempt < maxRetries {
+			time.Sleep(time.Duration(1<<attempt) * time.Second)
+		}
+	}
+	return lastErr
+}
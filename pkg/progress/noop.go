@@ -0,0 +1,15 @@
+package progress
+
+// NoopProgress discards all progress updates. It's used whenever redrawing a
+// TTY status line would pollute the output, e.g. JSON log consumers or
+// non-interactive stdout.
+type NoopProgress struct{}
+
+// Start implements Progress.
+func (p *NoopProgress) Start(total int) {}
+
+// Advance implements Progress.
+func (p *NoopProgress) Advance(label string) {}
+
+// Finish implements Progress.
+func (p *NoopProgress) Finish() {}
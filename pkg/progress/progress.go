@@ -0,0 +1,40 @@
+// Package progress reports live status for long-running org/web scans that
+// can otherwise iterate hundreds of targets silently for minutes at a time.
+package progress
+
+import (
+	"os"
+
+	"golang.org/x/term"
+)
+
+// Progress reports the status of a batch scan as it runs.
+type Progress interface {
+	// Start announces the total number of targets about to be scanned.
+	Start(total int)
+	// Advance records that one target finished and updates the label shown
+	// for "current target".
+	Advance(label string)
+	// Finish renders the final summary and stops redrawing.
+	Finish()
+}
+
+// CountingProgress is implemented by reporters that can fold running
+// vulnerable/safe package counts into their display (currently TTYProgress).
+type CountingProgress interface {
+	AddCounts(vulnerable, safe int)
+}
+
+// New returns a TTY reporter, unless output isn't a real terminal, JSON log
+// output was requested, or --no-progress was passed — in which case it
+// returns a no-op reporter so pipeline consumers don't see control characters.
+func New(noProgress bool, logFormat string) Progress {
+	if noProgress || logFormat == "json" || !isTerminal(os.Stdout) {
+		return &NoopProgress{}
+	}
+	return NewTTYProgress(os.Stdout)
+}
+
+func isTerminal(f *os.File) bool {
+	return term.IsTerminal(int(f.Fd()))
+}
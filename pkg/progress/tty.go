@@ -0,0 +1,77 @@
+package progress
+
+import (
+	"fmt"
+	"io"
+	"sync"
+	"time"
+)
+
+// TTYProgress redraws a single status line in place using ANSI escape codes,
+// showing total targets, the current target, elapsed/ETA, and running
+// vulnerable/safe package counts.
+type TTYProgress struct {
+	out io.Writer
+	mu  sync.Mutex
+
+	total     int
+	completed int
+	start     time.Time
+
+	vulnerable int
+	safe       int
+}
+
+// NewTTYProgress constructs a TTYProgress that redraws to out.
+func NewTTYProgress(out io.Writer) *TTYProgress {
+	return &TTYProgress{out: out}
+}
+
+// Start implements Progress.
+func (p *TTYProgress) Start(total int) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.total = total
+	p.start = time.Now()
+	p.redraw("starting...")
+}
+
+// Advance implements Progress.
+func (p *TTYProgress) Advance(label string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.completed++
+	p.redraw(label)
+}
+
+// AddCounts lets callers (e.g. the github org scan loop) fold a target's
+// vulnerable/safe package counts into the running totals shown in the bar.
+func (p *TTYProgress) AddCounts(vulnerable, safe int) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.vulnerable += vulnerable
+	p.safe += safe
+}
+
+// Finish implements Progress.
+func (p *TTYProgress) Finish() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.redraw("done")
+	fmt.Fprintln(p.out)
+}
+
+// redraw clears the current line and writes an updated status line. Must be
+// called with p.mu held.
+func (p *TTYProgress) redraw(label string) {
+	elapsed := time.Since(p.start).Round(time.Second)
+
+	var eta time.Duration
+	if p.completed > 0 && p.total > p.completed {
+		perTarget := elapsed / time.Duration(p.completed)
+		eta = perTarget * time.Duration(p.total-p.completed)
+	}
+
+	fmt.Fprintf(p.out, "\r\x1b[2K[%d/%d] %s | elapsed %s | eta %s | vulnerable=%d safe=%d",
+		p.completed, p.total, label, elapsed, eta, p.vulnerable, p.safe)
+}
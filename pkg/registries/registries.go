@@ -0,0 +1,201 @@
+// Package registries lets resolvers look beyond the ecosystem's single
+// hardcoded public registry, pointing them at private/internal mirrors
+// (Artifactory, Nexus, a self-hosted Packagist, etc.) with per-host auth.
+package registries
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Aware is implemented by resolvers that can be pointed at a configured
+// registries.Set so their availability checks cover private/internal
+// mirrors instead of only the ecosystem's hardcoded public default.
+type Aware interface {
+	SetRegistries(*Set)
+}
+
+// Registry describes a single package registry endpoint a resolver can
+// query, in addition to (or instead of) its ecosystem's public default.
+type Registry struct {
+	Ecosystem  string // npm, pip, composer, mvn, rubygems
+	URL        string
+	Internal   bool   // presence here does NOT count as "public" availability
+	AuthHeader string // optional "Header-Name: value" sent with every request
+
+	// Transport overrides for this specific endpoint; the zero value of each
+	// leaves the caller's default http.Client behavior untouched. These are
+	// only ever set via the registry_config section of confused.yaml, since
+	// the flag syntax has no room for them.
+	Proxy              string        // HTTP(S) proxy URL, e.g. for reaching an Artifactory mirror behind a corporate proxy
+	InsecureSkipVerify bool          // skip TLS verification, e.g. for a self-signed internal Nexus/Verdaccio
+	Timeout            time.Duration // overrides the resolver's own timeout when > 0
+}
+
+// ApplyAuth sets the registry's configured auth header on req, if any.
+func (r Registry) ApplyAuth(req *http.Request) {
+	if r.AuthHeader == "" {
+		return
+	}
+	name, value, ok := strings.Cut(r.AuthHeader, ":")
+	if !ok {
+		return
+	}
+	req.Header.Set(strings.TrimSpace(name), strings.TrimSpace(value))
+}
+
+// Client builds the http.Client a resolver should use to query this
+// registry, honoring its Proxy/InsecureSkipVerify/Timeout overrides. When
+// none are set and fallbackTimeout is 0, it returns http.DefaultClient so
+// the common case allocates nothing extra.
+func (r Registry) Client(fallbackTimeout time.Duration) *http.Client {
+	timeout := fallbackTimeout
+	if r.Timeout > 0 {
+		timeout = r.Timeout
+	}
+	if r.Proxy == "" && !r.InsecureSkipVerify {
+		if timeout <= 0 {
+			return http.DefaultClient
+		}
+		return &http.Client{Timeout: timeout}
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if r.Proxy != "" {
+		if proxyURL, err := url.Parse(r.Proxy); err == nil {
+			transport.Proxy = http.ProxyURL(proxyURL)
+		}
+	}
+	if r.InsecureSkipVerify {
+		if transport.TLSClientConfig == nil {
+			transport.TLSClientConfig = &tls.Config{}
+		}
+		transport.TLSClientConfig.InsecureSkipVerify = true
+	}
+	return &http.Client{Timeout: timeout, Transport: transport}
+}
+
+// EndpointConfig describes a single ecosystem's registry endpoint as
+// configured via confused.yaml's registry_config map, e.g.:
+//
+//	registry_config:
+//	  npm:
+//	    url: "https://nexus.internal/repository/npm-public"
+//	    auth_header: "Authorization: Bearer s3cr3t"
+//	    internal: false
+//	    timeout: 15
+//	    proxy: "http://proxy.internal:8080"
+//	    insecure_skip_verify: false
+//
+// This is the structured alternative to --registry/--internal-registry for
+// users who'd rather keep per-ecosystem mirror settings in one file.
+type EndpointConfig struct {
+	URL                string `mapstructure:"url"`
+	AuthHeader         string `mapstructure:"auth_header"`
+	Internal           bool   `mapstructure:"internal"`
+	Timeout            int    `mapstructure:"timeout"` // seconds; 0 leaves the resolver's own timeout in place
+	Proxy              string `mapstructure:"proxy"`
+	InsecureSkipVerify bool   `mapstructure:"insecure_skip_verify"`
+}
+
+// Set groups the registries configured via --registry/--internal-registry by
+// ecosystem. A nil *Set is valid and behaves as if nothing was configured.
+type Set struct {
+	byEcosystem map[string][]Registry
+}
+
+// NewSet builds a Set from --registry and --internal-registry flag values,
+// each formatted as "ecosystem=url" or "ecosystem=url|Header-Name: value".
+func NewSet(public, internal []string) (*Set, error) {
+	s := &Set{byEcosystem: make(map[string][]Registry)}
+	for _, raw := range public {
+		r, err := parseFlag(raw, false)
+		if err != nil {
+			return nil, err
+		}
+		s.byEcosystem[r.Ecosystem] = append(s.byEcosystem[r.Ecosystem], r)
+	}
+	for _, raw := range internal {
+		r, err := parseFlag(raw, true)
+		if err != nil {
+			return nil, err
+		}
+		s.byEcosystem[r.Ecosystem] = append(s.byEcosystem[r.Ecosystem], r)
+	}
+	return s, nil
+}
+
+// AddEndpoints merges the registry_config entries from confused.yaml into s,
+// one per ecosystem. It's called after NewSet so that --registry/
+// --internal-registry flags and registry_config can be combined; entries
+// added here take the same precedence as a flag-configured registry for
+// that ecosystem.
+func (s *Set) AddEndpoints(configs map[string]EndpointConfig) {
+	for ecosystem, ep := range configs {
+		if ep.URL == "" {
+			continue
+		}
+		s.byEcosystem[ecosystem] = append(s.byEcosystem[ecosystem], Registry{
+			Ecosystem:          ecosystem,
+			URL:                ep.URL,
+			Internal:           ep.Internal,
+			AuthHeader:         ep.AuthHeader,
+			Proxy:              ep.Proxy,
+			InsecureSkipVerify: ep.InsecureSkipVerify,
+			Timeout:            time.Duration(ep.Timeout) * time.Second,
+		})
+	}
+}
+
+func parseFlag(raw string, internal bool) (Registry, error) {
+	ecosystem, rest, ok := strings.Cut(raw, "=")
+	if !ok {
+		return Registry{}, fmt.Errorf("invalid registry flag %q: expected ecosystem=url", raw)
+	}
+	url, authHeader, _ := strings.Cut(rest, "|")
+	return Registry{
+		Ecosystem:  strings.TrimSpace(ecosystem),
+		URL:        strings.TrimSpace(url),
+		Internal:   internal,
+		AuthHeader: strings.TrimSpace(authHeader),
+	}, nil
+}
+
+// Public returns the public registries configured for ecosystem, falling
+// back to defaultURL when the user hasn't configured any.
+func (s *Set) Public(ecosystem, defaultURL string) []Registry {
+	if s == nil {
+		return []Registry{{Ecosystem: ecosystem, URL: defaultURL}}
+	}
+	var out []Registry
+	for _, r := range s.byEcosystem[ecosystem] {
+		if !r.Internal {
+			out = append(out, r)
+		}
+	}
+	if len(out) == 0 {
+		out = append(out, Registry{Ecosystem: ecosystem, URL: defaultURL})
+	}
+	return out
+}
+
+// Internal returns the registries marked "internal" for ecosystem. An empty
+// result means the caller configured no internal allowlist at all, which
+// resolvers treat as "presence there isn't required" rather than "never
+// internal".
+func (s *Set) Internal(ecosystem string) []Registry {
+	if s == nil {
+		return nil
+	}
+	var out []Registry
+	for _, r := range s.byEcosystem[ecosystem] {
+		if r.Internal {
+			out = append(out, r)
+		}
+	}
+	return out
+}
@@ -0,0 +1,65 @@
+package report
+
+import (
+	"encoding/json"
+	"io"
+
+	"github.com/h0tak88r/confused/internal/types"
+)
+
+// jsonDocument is the stable machine-readable schema the "json" --format
+// emits, so downstream SAST dashboards can diff scans without parsing the
+// human-readable summary.
+type jsonDocument struct {
+	Target    string        `json:"target"`
+	Ecosystem string        `json:"ecosystem"`
+	Findings  []jsonFinding `json:"findings"`
+}
+
+// jsonFinding describes one package's confusion-check outcome.
+type jsonFinding struct {
+	Name            string `json:"name"`
+	Ecosystem       string `json:"ecosystem"`
+	ManifestPath    string `json:"manifest_path"`
+	RegistryChecked bool   `json:"registry_checked"`
+	Status          string `json:"status"` // "confused" or "claimed"
+	NamespaceOwner  string `json:"namespace_owner,omitempty"`
+	EvidenceURL     string `json:"evidence_url,omitempty"`
+}
+
+// JSONReporter writes a scan result as a jsonDocument.
+type JSONReporter struct{}
+
+// Write renders result as JSON to w.
+func (r *JSONReporter) Write(w io.Writer, result *types.ScanResult) error {
+	doc := jsonDocument{
+		Target:    result.Target,
+		Ecosystem: result.Language,
+		Findings:  make([]jsonFinding, 0, len(result.Vulnerable)+len(result.Safe)),
+	}
+
+	for _, pkg := range result.Vulnerable {
+		doc.Findings = append(doc.Findings, jsonFinding{
+			Name:            pkg,
+			Ecosystem:       result.Language,
+			ManifestPath:    result.Target,
+			RegistryChecked: true,
+			Status:          "confused",
+			EvidenceURL:     registryDownloadLocation(result.Language, pkg),
+		})
+	}
+	for _, pkg := range result.Safe {
+		doc.Findings = append(doc.Findings, jsonFinding{
+			Name:            pkg,
+			Ecosystem:       result.Language,
+			ManifestPath:    result.Target,
+			RegistryChecked: true,
+			Status:          "claimed",
+			EvidenceURL:     registryDownloadLocation(result.Language, pkg),
+		})
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(doc)
+}
@@ -0,0 +1,29 @@
+// Package report renders scan results into the output formats supported by the
+// --format flag (text, json, html, sarif, spdx).
+package report
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/h0tak88r/confused/internal/types"
+)
+
+// Reporter writes a scan result to an output stream in a specific format.
+type Reporter interface {
+	Write(w io.Writer, result *types.ScanResult) error
+}
+
+// ForFormat returns the Reporter registered for the given --format value.
+func ForFormat(format string) (Reporter, error) {
+	switch format {
+	case "json":
+		return &JSONReporter{}, nil
+	case "sarif":
+		return &SARIFReporter{}, nil
+	case "spdx":
+		return &SPDXReporter{}, nil
+	default:
+		return nil, fmt.Errorf("unsupported report format: %s", format)
+	}
+}
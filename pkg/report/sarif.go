@@ -0,0 +1,122 @@
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/h0tak88r/confused/internal/types"
+)
+
+// sarifLog is a minimal SARIF 2.1.0 log containing a single run.
+type sarifLog struct {
+	Schema  string      `json:"$schema"`
+	Version string      `json:"version"`
+	Runs    []sarifRun  `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name    string      `json:"name"`
+	Version string      `json:"version"`
+	Rules   []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID               string             `json:"id"`
+	ShortDescription sarifMessage       `json:"shortDescription"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifResult struct {
+	RuleID              string                 `json:"ruleId"`
+	Level               string                 `json:"level"`
+	Message             sarifMessage           `json:"message"`
+	Locations           []sarifLocation        `json:"locations"`
+	PartialFingerprints map[string]string      `json:"partialFingerprints,omitempty"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           *sarifRegion          `json:"region,omitempty"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+// sarifRegion pins a result to a line within ArtifactLocation.URI. ScanResult
+// doesn't track which manifest line a package came from, so StartLine is
+// always 1 (pointing at the manifest itself) rather than a fabricated
+// per-package line number.
+type sarifRegion struct {
+	StartLine int `json:"startLine"`
+}
+
+// SARIFReporter writes a scan result as a SARIF 2.1.0 log.
+type SARIFReporter struct{}
+
+// Write renders result as a SARIF 2.1.0 document to w.
+func (r *SARIFReporter) Write(w io.Writer, result *types.ScanResult) error {
+	run := sarifRun{
+		Tool: sarifTool{
+			Driver: sarifDriver{
+				Name:    "confused",
+				Version: "2.2.0",
+				Rules: []sarifRule{
+					{
+						ID:               "dependency-confusion",
+						ShortDescription: sarifMessage{Text: "Package resolves to a private/internal namespace that is not claimed in the public registry"},
+					},
+				},
+			},
+		},
+		Results: make([]sarifResult, 0, len(result.Vulnerable)),
+	}
+
+	for _, pkg := range result.Vulnerable {
+		run.Results = append(run.Results, sarifResult{
+			RuleID: "dependency-confusion",
+			Level:  "warning",
+			Message: sarifMessage{
+				Text: fmt.Sprintf("Package %q is not available in the public %s registry and may be confusable.", pkg, result.Language),
+			},
+			Locations: []sarifLocation{
+				{
+					PhysicalLocation: sarifPhysicalLocation{
+						ArtifactLocation: sarifArtifactLocation{URI: result.Target},
+						Region:           &sarifRegion{StartLine: 1},
+					},
+				},
+			},
+			PartialFingerprints: map[string]string{
+				"dependencyConfusion/v1": fmt.Sprintf("%s:%s", result.Language, pkg),
+			},
+		})
+	}
+
+	log := sarifLog{
+		Schema:  "https://raw.githubusercontent.com/oasis-tcs/sarif-spec/master/Schemata/sarif-schema-2.1.0.json",
+		Version: "2.1.0",
+		Runs:    []sarifRun{run},
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(log)
+}
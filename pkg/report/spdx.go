@@ -0,0 +1,110 @@
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+
+	"github.com/h0tak88r/confused/internal/types"
+)
+
+// spdxDocument is a minimal SPDX 2.3 JSON document describing a scan target
+// and the packages discovered while scanning it.
+type spdxDocument struct {
+	SPDXVersion       string        `json:"spdxVersion"`
+	DataLicense       string        `json:"dataLicense"`
+	SPDXID            string        `json:"SPDXID"`
+	Name              string        `json:"name"`
+	DocumentNamespace string        `json:"documentNamespace"`
+	Packages          []spdxPackage `json:"packages"`
+	Relationships     []spdxRelationship `json:"relationships,omitempty"`
+}
+
+type spdxPackage struct {
+	SPDXID                  string `json:"SPDXID"`
+	PackageName             string `json:"name"`
+	PackageVersion          string `json:"versionInfo,omitempty"`
+	PackageDownloadLocation string `json:"downloadLocation"`
+	PackageComment          string `json:"comment,omitempty"`
+}
+
+type spdxRelationship struct {
+	SPDXElementID      string `json:"spdxElementId"`
+	RelationshipType   string `json:"relationshipType"`
+	RelatedSPDXElement string `json:"relatedSpdxElement"`
+}
+
+var spdxIDSanitizer = regexp.MustCompile(`[^A-Za-z0-9.\-]`)
+
+// SPDXReporter writes a scan result as an SPDX 2.3 SBOM document.
+type SPDXReporter struct{}
+
+// Write renders result as an SPDX 2.3 JSON document to w.
+func (r *SPDXReporter) Write(w io.Writer, result *types.ScanResult) error {
+	doc := spdxDocument{
+		SPDXVersion:       "SPDX-2.3",
+		DataLicense:       "CC0-1.0",
+		SPDXID:            "SPDXRef-DOCUMENT",
+		Name:              fmt.Sprintf("confused-scan-%s", result.Target),
+		DocumentNamespace: fmt.Sprintf("https://confused.local/spdx/%s-%d", spdxIDSanitizer.ReplaceAllString(result.Target, "-"), result.Timestamp.Unix()),
+	}
+
+	describes := make([]string, 0, len(result.Vulnerable)+len(result.Safe))
+
+	for _, pkg := range result.Vulnerable {
+		id := spdxPackageID(result.Language, pkg)
+		doc.Packages = append(doc.Packages, spdxPackage{
+			SPDXID:                  id,
+			PackageName:             pkg,
+			PackageDownloadLocation: registryDownloadLocation(result.Language, pkg),
+			PackageComment:          "NOASSERTION: not found in public registry, flagged as dependency confusion candidate",
+		})
+		describes = append(describes, id)
+	}
+
+	for _, pkg := range result.Safe {
+		id := spdxPackageID(result.Language, pkg)
+		doc.Packages = append(doc.Packages, spdxPackage{
+			SPDXID:                  id,
+			PackageName:             pkg,
+			PackageDownloadLocation: registryDownloadLocation(result.Language, pkg),
+		})
+		describes = append(describes, id)
+	}
+
+	for _, id := range describes {
+		doc.Relationships = append(doc.Relationships, spdxRelationship{
+			SPDXElementID:      "SPDXRef-DOCUMENT",
+			RelationshipType:   "DESCRIBES",
+			RelatedSPDXElement: id,
+		})
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(doc)
+}
+
+// spdxPackageID builds a stable SPDXID for a package within an ecosystem.
+func spdxPackageID(ecosystem, pkg string) string {
+	return "SPDXRef-Package-" + ecosystem + "-" + spdxIDSanitizer.ReplaceAllString(pkg, "-")
+}
+
+// registryDownloadLocation guesses the public registry URL for a package name.
+func registryDownloadLocation(ecosystem, pkg string) string {
+	switch ecosystem {
+	case "npm":
+		return "https://registry.npmjs.org/" + pkg
+	case "pip":
+		return "https://pypi.org/pypi/" + pkg + "/json"
+	case "composer":
+		return "https://packagist.org/packages/" + pkg + ".json"
+	case "mvn":
+		return "https://repo1.maven.org/maven2/" + pkg
+	case "rubygems":
+		return "https://rubygems.org/gems/" + pkg
+	default:
+		return "NOASSERTION"
+	}
+}
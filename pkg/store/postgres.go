@@ -0,0 +1,135 @@
+package store
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/h0tak88r/confused/internal/types"
+	"github.com/h0tak88r/confused/pkg/config"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const postgresSchema = `
+CREATE TABLE IF NOT EXISTS scan_results (
+	id         BIGSERIAL PRIMARY KEY,
+	target     TEXT        NOT NULL,
+	language   TEXT        NOT NULL,
+	timestamp  TIMESTAMPTZ NOT NULL,
+	vulnerable JSONB       NOT NULL,
+	safe       JSONB       NOT NULL,
+	metadata   JSONB       NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_scan_results_target ON scan_results(target, timestamp);
+`
+
+// PostgresStore is a pgx-backed ResultStore.
+type PostgresStore struct {
+	pool *pgxpool.Pool
+	ctx  context.Context
+}
+
+// NewPostgresStore connects to the database described by cfg and ensures the
+// scan_results table exists.
+func NewPostgresStore(cfg config.DatabaseConfig) (*PostgresStore, error) {
+	ctx := context.Background()
+
+	dsn := fmt.Sprintf("postgres://%s:%s@%s:%d/%s", cfg.Username, cfg.Password, cfg.Host, cfg.Port, cfg.Database)
+	pool, err := pgxpool.New(ctx, dsn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to postgresql: %w", err)
+	}
+
+	if _, err := pool.Exec(ctx, postgresSchema); err != nil {
+		pool.Close()
+		return nil, fmt.Errorf("failed to initialize postgresql schema: %w", err)
+	}
+
+	return &PostgresStore{pool: pool, ctx: ctx}, nil
+}
+
+// SaveResult implements ResultStore.
+func (s *PostgresStore) SaveResult(result *types.ScanResult) error {
+	vulnerable, safe, metadata, err := marshalResult(result)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.pool.Exec(s.ctx,
+		`INSERT INTO scan_results (target, language, timestamp, vulnerable, safe, metadata) VALUES ($1, $2, $3, $4, $5, $6)`,
+		result.Target, result.Language, result.Timestamp, vulnerable, safe, metadata,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to save scan result: %w", err)
+	}
+	return nil
+}
+
+// ListResults implements ResultStore.
+func (s *PostgresStore) ListResults(target string) ([]*types.ScanResult, error) {
+	rows, err := s.pool.Query(s.ctx,
+		`SELECT id, target, language, timestamp, vulnerable, safe, metadata FROM scan_results WHERE target = $1 ORDER BY timestamp DESC`,
+		target,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list scan results: %w", err)
+	}
+	defer rows.Close()
+
+	var results []*types.ScanResult
+	for rows.Next() {
+		var (
+			id                       int64
+			targetCol, language, ts  string
+			vulnerableJSON, safeJSON string
+			metadataJSON             string
+		)
+		if err := rows.Scan(&id, &targetCol, &language, &ts, &vulnerableJSON, &safeJSON, &metadataJSON); err != nil {
+			return nil, fmt.Errorf("failed to scan result row: %w", err)
+		}
+		result, err := unmarshalResult(id, targetCol, language, ts, vulnerableJSON, safeJSON, metadataJSON)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, result)
+	}
+	return results, rows.Err()
+}
+
+// GetResult implements ResultStore.
+func (s *PostgresStore) GetResult(id string) (*types.ScanResult, error) {
+	row := s.pool.QueryRow(s.ctx,
+		`SELECT id, target, language, timestamp, vulnerable, safe, metadata FROM scan_results WHERE id = $1`,
+		id,
+	)
+
+	var (
+		rowID                          int64
+		target, language, ts           string
+		vulnerableJSON, safeJSON       string
+		metadataJSON                   string
+	)
+	if err := row.Scan(&rowID, &target, &language, &ts, &vulnerableJSON, &safeJSON, &metadataJSON); err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, fmt.Errorf("no scan result found with id %s", id)
+		}
+		return nil, fmt.Errorf("failed to scan result row: %w", err)
+	}
+
+	return unmarshalResult(rowID, target, language, ts, vulnerableJSON, safeJSON, metadataJSON)
+}
+
+// DiffAgainstPrevious implements ResultStore.
+func (s *PostgresStore) DiffAgainstPrevious(target string) (*Diff, error) {
+	results, err := s.ListResults(target)
+	if err != nil {
+		return nil, err
+	}
+	return diffLastTwo(target, results)
+}
+
+// Close implements ResultStore.
+func (s *PostgresStore) Close() error {
+	s.pool.Close()
+	return nil
+}
@@ -0,0 +1,120 @@
+package store
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/h0tak88r/confused/internal/types"
+)
+
+// timestampLayout is the on-disk representation of ScanResult.Timestamp,
+// chosen so lexical and chronological ordering agree.
+const timestampLayout = time.RFC3339Nano
+
+// resultRow is the subset of the sql.Row/sql.Rows interface that scanRow/scanRows need.
+type resultRow interface {
+	Scan(dest ...interface{}) error
+}
+
+// scanRow reads a single scan_results row into a *types.ScanResult.
+func scanRow(row resultRow) (*types.ScanResult, error) {
+	var (
+		id                            int64
+		target, language, ts          string
+		vulnerableJSON, safeJSON      string
+		metadataJSON                  string
+	)
+
+	if err := row.Scan(&id, &target, &language, &ts, &vulnerableJSON, &safeJSON, &metadataJSON); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("no scan result found")
+		}
+		return nil, fmt.Errorf("failed to scan result row: %w", err)
+	}
+
+	return unmarshalResult(id, target, language, ts, vulnerableJSON, safeJSON, metadataJSON)
+}
+
+// scanRows reads every row of a scan_results query into []*types.ScanResult.
+func scanRows(rows *sql.Rows) ([]*types.ScanResult, error) {
+	var results []*types.ScanResult
+	for rows.Next() {
+		result, err := scanRow(rows)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, result)
+	}
+	return results, rows.Err()
+}
+
+func unmarshalResult(id int64, target, language, ts, vulnerableJSON, safeJSON, metadataJSON string) (*types.ScanResult, error) {
+	timestamp, err := time.Parse(timestampLayout, ts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse stored timestamp %q: %w", ts, err)
+	}
+
+	var vulnerable, safe []string
+	if err := json.Unmarshal([]byte(vulnerableJSON), &vulnerable); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal vulnerable packages: %w", err)
+	}
+	if err := json.Unmarshal([]byte(safeJSON), &safe); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal safe packages: %w", err)
+	}
+
+	metadata := map[string]interface{}{}
+	if err := json.Unmarshal([]byte(metadataJSON), &metadata); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal metadata: %w", err)
+	}
+	metadata["result_id"] = id
+
+	return &types.ScanResult{
+		Target:     target,
+		Language:   language,
+		Timestamp:  timestamp,
+		Vulnerable: vulnerable,
+		Safe:       safe,
+		Total:      len(vulnerable) + len(safe),
+		Metadata:   metadata,
+	}, nil
+}
+
+// diffLastTwo compares the two most recent results (results must be sorted
+// newest-first) and reports packages that became/stopped being vulnerable.
+func diffLastTwo(target string, results []*types.ScanResult) (*Diff, error) {
+	if len(results) < 2 {
+		return nil, fmt.Errorf("need at least 2 stored scans of %s to diff, have %d", target, len(results))
+	}
+
+	current, previous := results[0], results[1]
+
+	previousVulnerable := make(map[string]bool, len(previous.Vulnerable))
+	for _, pkg := range previous.Vulnerable {
+		previousVulnerable[pkg] = true
+	}
+	currentVulnerable := make(map[string]bool, len(current.Vulnerable))
+	for _, pkg := range current.Vulnerable {
+		currentVulnerable[pkg] = true
+	}
+
+	diff := &Diff{
+		Target:   target,
+		Previous: previous.Timestamp.Format(timestampLayout),
+		Current:  current.Timestamp.Format(timestampLayout),
+	}
+
+	for pkg := range currentVulnerable {
+		if !previousVulnerable[pkg] {
+			diff.NewlyVulnerable = append(diff.NewlyVulnerable, pkg)
+		}
+	}
+	for pkg := range previousVulnerable {
+		if !currentVulnerable[pkg] {
+			diff.NoLongerVulnerable = append(diff.NoLongerVulnerable, pkg)
+		}
+	}
+
+	return diff, nil
+}
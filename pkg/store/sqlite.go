@@ -0,0 +1,126 @@
+package store
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"github.com/h0tak88r/confused/internal/types"
+	_ "modernc.org/sqlite"
+)
+
+const sqliteSchema = `
+CREATE TABLE IF NOT EXISTS scan_results (
+	id         INTEGER PRIMARY KEY AUTOINCREMENT,
+	target     TEXT    NOT NULL,
+	language   TEXT    NOT NULL,
+	timestamp  TEXT    NOT NULL,
+	vulnerable TEXT    NOT NULL,
+	safe       TEXT    NOT NULL,
+	metadata   TEXT    NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_scan_results_target ON scan_results(target, timestamp);
+`
+
+// SQLiteStore is a pure-Go (modernc.org/sqlite) ResultStore backend.
+type SQLiteStore struct {
+	db *sql.DB
+}
+
+// NewSQLiteStore opens (creating if necessary) a SQLite database at path and
+// ensures the scan_results table exists.
+func NewSQLiteStore(path string) (*SQLiteStore, error) {
+	if path == "" {
+		path = "confused-history.db"
+	}
+
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open sqlite database: %w", err)
+	}
+
+	if _, err := db.Exec(sqliteSchema); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to initialize sqlite schema: %w", err)
+	}
+
+	return &SQLiteStore{db: db}, nil
+}
+
+// SaveResult implements ResultStore.
+func (s *SQLiteStore) SaveResult(result *types.ScanResult) error {
+	vulnerable, safe, metadata, err := marshalResult(result)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.db.Exec(
+		`INSERT INTO scan_results (target, language, timestamp, vulnerable, safe, metadata) VALUES (?, ?, ?, ?, ?, ?)`,
+		result.Target, result.Language, result.Timestamp.Format(timestampLayout), vulnerable, safe, metadata,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to save scan result: %w", err)
+	}
+	return nil
+}
+
+// ListResults implements ResultStore.
+func (s *SQLiteStore) ListResults(target string) ([]*types.ScanResult, error) {
+	rows, err := s.db.Query(
+		`SELECT id, target, language, timestamp, vulnerable, safe, metadata FROM scan_results WHERE target = ? ORDER BY timestamp DESC`,
+		target,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list scan results: %w", err)
+	}
+	defer rows.Close()
+
+	return scanRows(rows)
+}
+
+// GetResult implements ResultStore.
+func (s *SQLiteStore) GetResult(id string) (*types.ScanResult, error) {
+	rowID, err := strconv.ParseInt(id, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid result id %q: %w", id, err)
+	}
+
+	row := s.db.QueryRow(
+		`SELECT id, target, language, timestamp, vulnerable, safe, metadata FROM scan_results WHERE id = ?`,
+		rowID,
+	)
+	return scanRow(row)
+}
+
+// DiffAgainstPrevious implements ResultStore.
+func (s *SQLiteStore) DiffAgainstPrevious(target string) (*Diff, error) {
+	results, err := s.ListResults(target)
+	if err != nil {
+		return nil, err
+	}
+	return diffLastTwo(target, results)
+}
+
+// Close implements ResultStore.
+func (s *SQLiteStore) Close() error {
+	return s.db.Close()
+}
+
+// marshalResult encodes the vulnerable/safe package lists and metadata as JSON
+// for storage in a JSON/JSONB column.
+func marshalResult(result *types.ScanResult) (vulnerable, safe, metadata string, err error) {
+	vulnerableBytes, err := json.Marshal(result.Vulnerable)
+	if err != nil {
+		return "", "", "", fmt.Errorf("failed to marshal vulnerable packages: %w", err)
+	}
+	safeBytes, err := json.Marshal(result.Safe)
+	if err != nil {
+		return "", "", "", fmt.Errorf("failed to marshal safe packages: %w", err)
+	}
+	metadataBytes, err := json.Marshal(result.Metadata)
+	if err != nil {
+		return "", "", "", fmt.Errorf("failed to marshal metadata: %w", err)
+	}
+	return string(vulnerableBytes), string(safeBytes), string(metadataBytes), nil
+}
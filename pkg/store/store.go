@@ -0,0 +1,52 @@
+// Package store persists scan results so that repeated scans of the same
+// target can be listed and diffed over time, instead of only ever being
+// dumped to timestamped JSON files.
+package store
+
+import (
+	"fmt"
+
+	"github.com/h0tak88r/confused/internal/types"
+	"github.com/h0tak88r/confused/pkg/config"
+)
+
+// ResultStore persists and queries historical scan results.
+type ResultStore interface {
+	// SaveResult persists a single scan result, keyed by (target, language, timestamp).
+	SaveResult(result *types.ScanResult) error
+	// ListResults returns every stored result for target, newest first.
+	ListResults(target string) ([]*types.ScanResult, error)
+	// GetResult returns the result stored under id, as produced by SaveResult.
+	GetResult(id string) (*types.ScanResult, error)
+	// DiffAgainstPrevious compares the two most recent results for target and
+	// returns the packages that newly appeared as vulnerable.
+	DiffAgainstPrevious(target string) (*Diff, error)
+	// Close releases any underlying connection/handle.
+	Close() error
+}
+
+// Diff describes how a target's vulnerable package set changed between its
+// two most recent scans.
+type Diff struct {
+	Target       string   `json:"target"`
+	Previous     string   `json:"previous_timestamp"`
+	Current      string   `json:"current_timestamp"`
+	NewlyVulnerable []string `json:"newly_vulnerable"`
+	NoLongerVulnerable []string `json:"no_longer_vulnerable"`
+}
+
+// New returns the ResultStore configured by cfg.Database.Type ("sqlite" or
+// "postgresql"). It returns (nil, nil) when no database is configured, so
+// callers can treat persistence as optional.
+func New(cfg *config.Config) (ResultStore, error) {
+	switch cfg.Database.Type {
+	case "":
+		return nil, nil
+	case "sqlite":
+		return NewSQLiteStore(cfg.Database.Database)
+	case "postgresql":
+		return NewPostgresStore(cfg.Database)
+	default:
+		return nil, fmt.Errorf("unsupported database type: %s", cfg.Database.Type)
+	}
+}
@@ -0,0 +1,194 @@
+package web
+
+import (
+	"encoding/xml"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+)
+
+// discoverySources lists the discovery methods recognized by --discovery.
+// "commoncrawl" is accepted but not yet implemented: querying the CommonCrawl
+// index requires an out-of-band index API call this scanner doesn't make
+// elsewhere, so for now it's logged as skipped rather than silently ignored.
+const (
+	discoveryRobots      = "robots"
+	discoverySitemap     = "sitemap"
+	discoveryCommonCrawl = "commoncrawl"
+)
+
+// urlset/url mirror the small part of the sitemap.xml schema we care about:
+// the <loc> of each entry.
+type sitemapURLSet struct {
+	XMLName xml.Name     `xml:"urlset"`
+	URLs    []sitemapURL `xml:"url"`
+}
+
+type sitemapURL struct {
+	Loc string `xml:"loc"`
+}
+
+// SetDiscovery configures which additional-root discovery sources ScanTarget
+// and deepScan consult beyond the hardcoded common directory list, e.g.
+// []string{"robots", "sitemap"}. An unset or empty list disables discovery.
+func (s *Scanner) SetDiscovery(sources []string) {
+	s.discovery = sources
+}
+
+// hasDiscoverySource reports whether name was requested via SetDiscovery.
+func (s *Scanner) hasDiscoverySource(name string) bool {
+	for _, d := range s.discovery {
+		if d == name {
+			return true
+		}
+	}
+	return false
+}
+
+// discoverRoots fetches robots.txt and/or sitemap.xml (per the configured
+// discovery sources) and returns candidate directory roots extracted from
+// them, in addition to the hardcoded commonDirs list already tried by
+// deepScan. Fetch failures are non-fatal: an unreachable or missing
+// robots.txt/sitemap.xml just yields no extra roots.
+func (s *Scanner) discoverRoots(baseURL *url.URL) []string {
+	var roots []string
+
+	if s.hasDiscoverySource(discoveryRobots) {
+		roots = append(roots, s.discoverFromRobots(baseURL)...)
+	}
+	if s.hasDiscoverySource(discoverySitemap) {
+		roots = append(roots, s.discoverFromSitemap(baseURL)...)
+	}
+	if s.hasDiscoverySource(discoveryCommonCrawl) {
+		s.logger.Debug("commoncrawl discovery requested but not yet implemented, skipping")
+	}
+
+	return dedupeStrings(roots)
+}
+
+// discoverFromRobots fetches /robots.txt and treats every "Disallow:" path as
+// a candidate directory to probe for dependency files — hidden app roots are
+// disproportionately likely to show up there.
+func (s *Scanner) discoverFromRobots(baseURL *url.URL) []string {
+	body, err := s.fetchText(baseURL, "/robots.txt")
+	if err != nil {
+		s.logger.Debug("Failed to fetch robots.txt: %v", err)
+		return nil
+	}
+
+	var roots []string
+	for _, line := range strings.Split(body, "\n") {
+		line = strings.TrimSpace(line)
+		idx := strings.Index(strings.ToLower(line), "disallow:")
+		if idx != 0 {
+			continue
+		}
+		dir := strings.TrimSpace(line[len("disallow:"):])
+		if dir == "" || dir == "/" {
+			continue
+		}
+		roots = append(roots, toDir(dir))
+	}
+	return roots
+}
+
+// discoverFromSitemap fetches /sitemap.xml and returns the directory of each
+// listed URL, so e.g. a sitemap entry for /api/v2/docs surfaces "api/v2/" as
+// a root to probe for dependency files.
+func (s *Scanner) discoverFromSitemap(baseURL *url.URL) []string {
+	body, err := s.fetchText(baseURL, "/sitemap.xml")
+	if err != nil {
+		s.logger.Debug("Failed to fetch sitemap.xml: %v", err)
+		return nil
+	}
+
+	var set sitemapURLSet
+	if err := xml.Unmarshal([]byte(body), &set); err != nil {
+		s.logger.Debug("Failed to parse sitemap.xml: %v", err)
+		return nil
+	}
+
+	var roots []string
+	for _, entry := range set.URLs {
+		u, err := url.Parse(entry.Loc)
+		if err != nil || u.Path == "" {
+			continue
+		}
+		roots = append(roots, toDir(u.Path))
+	}
+	return roots
+}
+
+// fetchText GETs relPath against baseURL's host and returns the response body
+// as a string. Non-200 responses are treated as errors.
+func (s *Scanner) fetchText(baseURL *url.URL, relPath string) (string, error) {
+	target := *baseURL
+	target.Path = relPath
+
+	req, err := http.NewRequestWithContext(s.ctx, "GET", target.String(), nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("User-Agent", s.userAgent)
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", &discoveryFetchError{path: relPath, statusCode: resp.StatusCode}
+	}
+
+	content, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", err
+	}
+	return string(content), nil
+}
+
+type discoveryFetchError struct {
+	path       string
+	statusCode int
+}
+
+func (e *discoveryFetchError) Error() string {
+	return e.path + ": unexpected status " + http.StatusText(e.statusCode)
+}
+
+// toDir normalizes an arbitrary path into a directory root suitable for
+// scanDirectory: strips any file component and ensures a trailing slash.
+func toDir(p string) string {
+	p = strings.TrimPrefix(p, "/")
+	if p == "" {
+		return ""
+	}
+	dir := path.Dir(p)
+	if strings.Contains(path.Base(p), ".") {
+		// Looks like a file (has an extension); use its containing directory.
+		p = dir
+	} else {
+		p = strings.TrimSuffix(p, "/")
+	}
+	if p == "" || p == "." {
+		return ""
+	}
+	return p + "/"
+}
+
+// dedupeStrings removes duplicate and empty entries while preserving order.
+func dedupeStrings(in []string) []string {
+	seen := make(map[string]bool, len(in))
+	out := make([]string, 0, len(in))
+	for _, s := range in {
+		if s == "" || seen[s] {
+			continue
+		}
+		seen[s] = true
+		out = append(out, s)
+	}
+	return out
+}
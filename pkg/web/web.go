@@ -1,6 +1,7 @@
 package web
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"net/http"
@@ -13,14 +14,21 @@ import (
 	"github.com/h0tak88r/confused/internal/resolvers"
 	"github.com/h0tak88r/confused/internal/types"
 	"github.com/h0tak88r/confused/pkg/logger"
+	"github.com/h0tak88r/confused/pkg/registries"
+	"golang.org/x/time/rate"
 )
 
 // Scanner represents a web dependency scanner
 type Scanner struct {
-	client    *http.Client
-	logger    *logger.Logger
-	userAgent string
-	timeout   time.Duration
+	client      *http.Client
+	logger      *logger.Logger
+	userAgent   string
+	timeout     time.Duration
+	ctx         context.Context
+	limiter     *rate.Limiter
+	registrySet *registries.Set
+	workers     int
+	discovery   []string
 }
 
 // New creates a new web scanner
@@ -32,6 +40,34 @@ func New(log *logger.Logger, userAgent string, timeout time.Duration) *Scanner {
 		logger:    log,
 		userAgent: userAgent,
 		timeout:   timeout,
+		ctx:       context.Background(),
+		workers:   10,
+	}
+}
+
+// SetContext points the scanner at a cancellable root context, so SIGINT/
+// SIGTERM during a web scan stops in-flight HTTP requests instead of running
+// to completion.
+func (s *Scanner) SetContext(ctx context.Context) {
+	s.ctx = ctx
+}
+
+// SetLimiter points this scanner's resolvers at a shared rate limiter.
+func (s *Scanner) SetLimiter(limiter *rate.Limiter) {
+	s.limiter = limiter
+}
+
+// SetRegistries points this scanner's resolvers at the configured
+// public/internal registry mirrors.
+func (s *Scanner) SetRegistries(regs *registries.Set) {
+	s.registrySet = regs
+}
+
+// SetWorkers points this scanner's resolvers at the configured --workers
+// concurrency for their registry probes.
+func (s *Scanner) SetWorkers(workers int) {
+	if workers > 0 {
+		s.workers = workers
 	}
 }
 
@@ -135,7 +171,7 @@ func (s *Scanner) scanDependencyFile(baseURL *url.URL, filePath string, language
 	s.logger.Debug("Checking: %s", fileURL.String())
 	
 	// Make HTTP request
-	req, err := http.NewRequest("GET", fileURL.String(), nil)
+	req, err := http.NewRequestWithContext(s.ctx, "GET", fileURL.String(), nil)
 	if err != nil {
 		return nil, err
 	}
@@ -191,7 +227,10 @@ func (s *Scanner) scanDependencyFile(baseURL *url.URL, filePath string, language
 		return nil, err
 	}
 	
-	// Get vulnerable packages
+	// Get vulnerable packages, aborting early if the context was cancelled
+	if err := resolver.ResolveWithContext(s.ctx); err != nil {
+		return nil, fmt.Errorf("scan cancelled: %w", err)
+	}
 	vulnerablePackages := resolver.PackagesNotInPublic()
 	
 	// Add to result
@@ -214,13 +253,20 @@ func (s *Scanner) scanDependencyFile(baseURL *url.URL, filePath string, language
 // deepScan performs additional discovery methods
 func (s *Scanner) deepScan(baseURL *url.URL, languages []string, maxDepth int) ([]*types.ScanResult, error) {
 	var results []*types.ScanResult
-	
+
 	// Try common directory paths
 	commonDirs := []string{
 		"src/", "lib/", "app/", "web/", "public/", "static/",
 		"api/", "backend/", "frontend/", "client/", "server/",
 	}
-	
+
+	// Supplement the hardcoded list with any app roots surfaced by the
+	// configured discovery sources (robots.txt Disallow entries, sitemap.xml
+	// URLs), so deep scans aren't limited to guessed directory names.
+	if len(s.discovery) > 0 {
+		commonDirs = dedupeStrings(append(commonDirs, s.discoverRoots(baseURL)...))
+	}
+
 	for _, dir := range commonDirs {
 		dirResults, err := s.scanDirectory(baseURL, dir, languages)
 		if err != nil {
@@ -279,7 +325,7 @@ func (s *Scanner) getLanguageFromFile(filePath string) string {
 
 // getResolverForLanguage returns the appropriate resolver for a language
 func (s *Scanner) getResolverForLanguage(language string) (types.PackageResolver, error) {
-	return resolvers.GetResolverForLanguage(language)
+	return resolvers.GetResolverForLanguageWithContext(language, false, s.ctx, s.limiter, s.registrySet, s.logger, s.workers)
 }
 
 // createTempFile creates a temporary file with the given content